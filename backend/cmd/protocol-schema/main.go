@@ -0,0 +1,29 @@
+// Command protocol-schema writes the JSON Schema of the WebSocket protocol.
+// Run it through go generate in internal/protocol after changing a message.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	data, err := protocol.SchemaJSON()
+	if err != nil {
+		log.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatalf("Failed to write schema: %v", err)
+	}
+}
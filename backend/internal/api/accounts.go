@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/4-in-a-row/internal/auth"
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// Players join in one of three ways: registered players with the signed
+// token handed out by /api/auth/register and /api/auth/login, bot accounts
+// with an API key (see bots.go), and guests, who pick any username no
+// account owns and keep no stats.
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+// credentials is the body of the register and login endpoints
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (c *credentials) Validate() error {
+	if !usernamePattern.MatchString(c.Username) {
+		return errors.New("username must be 3 to 32 letters, digits, '_' or '-'")
+	}
+	// bcrypt ignores everything past 72 bytes
+	if len(c.Password) < 8 || len(c.Password) > 72 {
+		return errors.New("password must be 8 to 72 bytes")
+	}
+	return nil
+}
+
+// newPlayer creates the player for a join request, authenticated by a bot
+// API key, an account token or, for guests, nothing at all
+func (s *Server) newPlayer(ctx context.Context, apiKey string, join *protocol.Join) (*game.Player, error) {
+	switch {
+	case apiKey != "":
+		return s.botPlayer(ctx, apiKey, join.Username)
+
+	case join.Token != "":
+		claims, err := s.auth.Verify(join.Token)
+		if err != nil {
+			return nil, err
+		}
+		if join.Username != "" && join.Username != claims.Username {
+			return nil, fmt.Errorf("%w: token belongs to %s", protocol.ErrUnauthorized, claims.Username)
+		}
+		user, err := s.db.GetUserStats(ctx, claims.Username)
+		if err != nil {
+			return nil, err
+		}
+		player := s.gameManager.NewPlayer(user.Username)
+		player.Rating = float64(user.Rating)
+		return player, nil
+
+	case join.Guest:
+		if s.gameManager.IsHostedName(join.Username) {
+			return nil, fmt.Errorf("%w: %s is reserved for bots", database.ErrUsernameTaken, join.Username)
+		}
+		owned, err := s.db.IsUsernameOwned(ctx, join.Username)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			return nil, fmt.Errorf("%w: %s belongs to a registered player", database.ErrUsernameTaken, join.Username)
+		}
+		return s.gameManager.NewPlayer(join.Username), nil
+	}
+
+	return nil, fmt.Errorf("%w: log in or join as a guest", protocol.ErrUnauthorized)
+}
+
+// activeSession returns the session token of the matchmaking game the
+// player behind a join is already waiting for or playing, so that joining
+// again resumes it. Guests prove nothing about who they are, so for them a
+// name in use stays an error rather than a way into someone's game.
+func (s *Server) activeSession(apiKey string, join *protocol.Join, username string) (string, bool) {
+	if apiKey == "" && join.Guest {
+		return "", false
+	}
+	_, player, err := s.gameManager.ActivePlayer(username)
+	if err != nil {
+		return "", false
+	}
+	return player.SessionToken, true
+}
+
+// requestAccount returns the account behind a request's X-API-Key header,
+// for bots, or its "Authorization: Bearer <token>" account token
+func (s *Server) requestAccount(r *http.Request) (*database.User, error) {
+	if apiKey := r.Header.Get(apiKeyHeader); apiKey != "" {
+		account, err := s.db.GetBotByAPIKey(r.Context(), hashAPIKey(apiKey))
+		if errors.Is(err, database.ErrUserNotFound) {
+			return nil, fmt.Errorf("%w: invalid API key", protocol.ErrUnauthorized)
+		}
+		return account, err
+	}
+
+	claims, err := s.auth.Verify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if err != nil {
+		return nil, err
+	}
+	return s.db.GetUserStats(r.Context(), claims.Username)
+}
+
+// handleRegister creates an account and logs it in
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var data credentials
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if s.gameManager.IsHostedName(data.Username) {
+		respondAPIError(w, database.ErrUsernameTaken)
+		return
+	}
+
+	hash, err := auth.HashPassword(data.Password)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	user, err := s.db.CreateAccount(r.Context(), data.Username, hash)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	s.respondToken(w, http.StatusCreated, user)
+}
+
+// handleLogin exchanges a username and password for a token
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var data credentials
+	if err := decodeBody(r, &data); err != nil {
+		// Malformed credentials cannot belong to any account
+		respondAPIError(w, auth.ErrInvalidCredentials)
+		return
+	}
+
+	user, hash, err := s.db.GetAccount(r.Context(), data.Username)
+	if errors.Is(err, database.ErrUserNotFound) {
+		respondAPIError(w, auth.ErrInvalidCredentials)
+		return
+	}
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if !auth.CheckPassword(hash, data.Password) {
+		respondAPIError(w, auth.ErrInvalidCredentials)
+		return
+	}
+
+	s.respondToken(w, http.StatusOK, user)
+}
+
+// handleMe returns the account a token belongs to
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	claims, err := s.auth.Verify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	user, err := s.db.GetUserStats(r.Context(), claims.Username)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) respondToken(w http.ResponseWriter, status int, user *database.User) {
+	token, expiresAt, err := s.auth.Issue(user.ID, user.Username)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, status, map[string]interface{}{
+		"user":       user,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
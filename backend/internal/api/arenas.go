@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yourusername/4-in-a-row/internal/arena"
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// Arenas are scheduled by an operator through the admin API. While one
+// runs, players join its queue with a regular join; after each game they
+// are sent the leaderboard and, if still connected, a player_info for the
+// next game they were queued for.
+
+// arenaSettings is the body of handleCreateArena
+type arenaSettings struct {
+	Name           string    `json:"name"`
+	StartsAt       time.Time `json:"starts_at"` // zero or past to start straight away
+	DurationMin    int       `json:"duration_min"`
+	TurnTimeoutSec int       `json:"turn_timeout_sec,omitempty"` // 0 for the server's
+	Rated          bool      `json:"rated"`
+}
+
+func (a *arenaSettings) Validate() error {
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Name == "" || len(a.Name) > 64 {
+		return errors.New("name must be 1 to 64 characters")
+	}
+	if a.DurationMin < 1 || a.DurationMin > 24*60 {
+		return errors.New("duration_min must be 1 to 1440")
+	}
+	if a.TurnTimeoutSec != 0 && (a.TurnTimeoutSec < 5 || a.TurnTimeoutSec > 600) {
+		return errors.New("turn_timeout_sec must be 0 or 5 to 600")
+	}
+	return nil
+}
+
+// handleListArenas lists arenas, the latest to start first, optionally only
+// those with ?status=
+func (s *Server) handleListArenas(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	arenas, err := s.arenas.List(r.Context(), limit, r.URL.Query().Get("status"))
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if arenas == nil {
+		arenas = []database.Arena{}
+	}
+
+	respondJSON(w, http.StatusOK, arenas)
+}
+
+// handleGetArena returns an arena with its leaderboard
+func (s *Server) handleGetArena(w http.ResponseWriter, r *http.Request) {
+	a, err := s.arenas.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, a)
+}
+
+// handleCreateArena schedules an arena
+func (s *Server) handleCreateArena(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	var data arenaSettings
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	a, err := s.arenas.Create(r.Context(), data.Name, data.StartsAt,
+		time.Duration(data.DurationMin)*time.Minute, time.Duration(data.TurnTimeoutSec)*time.Second, data.Rated)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, a)
+}
+
+// sendArenaLeaderboard sends an arena's leaderboard to the clients of the
+// players in it
+func (s *Server) sendArenaLeaderboard(a *arena.Arena, playerIDs []string) {
+	payload := protocol.NewArenaLeaderboard(a)
+	for _, playerID := range playerIDs {
+		for _, client := range s.playerClients(playerID) {
+			client.sendMessage(protocol.TypeArenaLeaderboard, payload)
+		}
+	}
+}
+
+// followRequeue moves the clients of a player who finished an arena game on
+// to the player the arena queued again, as if they had just joined, or ends
+// their search if the arena could not queue them
+func (s *Server) followRequeue(previousID string, player *game.Player, gameObj *game.Game) {
+	if player == nil {
+		s.endSearch(previousID, "Could not join the arena queue again")
+		return
+	}
+	for _, client := range s.playerClients(previousID) {
+		client.sendMessage(protocol.TypePlayerInfo, protocol.PlayerInfo{
+			PlayerID:     player.ID,
+			GameID:       gameObj.ID,
+			Username:     player.Username,
+			SessionToken: player.SessionToken,
+		})
+		s.attachToGame(client, player.ID, gameObj, nil)
+
+		if status, ok := s.matchmaker.QueueStatus(player.ID); ok {
+			client.sendMessage(protocol.TypeWaiting, protocol.Waiting{
+				Message: "Waiting for opponent...",
+			})
+			client.sendMessage(protocol.TypeQueueStatus, protocol.NewQueueStatus(status))
+		}
+	}
+}
+
+// closeArenaSearches tells the players still waiting when an arena ended
+// that their search is over
+func (s *Server) closeArenaSearches(waiting []*game.Player) {
+	for _, player := range waiting {
+		s.endSearch(player.ID, "Arena is over")
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// Bot accounts let engines written by anyone play through the public APIs.
+// An operator registers an account through the admin API and hands out its
+// API key; the bot's client sends the key as an X-API-Key header (x-api-key
+// metadata over gRPC) when joining, and then plays like any other client.
+// Only the key's SHA-256 is stored.
+
+const apiKeyHeader = "X-API-Key"
+
+// newAPIKey generates a bot API key and the hash stored for it
+func newAPIKey() (key, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	key = "bot_" + hex.EncodeToString(buf)
+	return key, hashAPIKey(key), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// botPlayer creates the player for the bot account an API key belongs to.
+// username must be the account's.
+func (s *Server) botPlayer(ctx context.Context, apiKey, username string) (*game.Player, error) {
+	account, err := s.db.GetBotByAPIKey(ctx, hashAPIKey(apiKey))
+	if errors.Is(err, database.ErrUserNotFound) {
+		return nil, fmt.Errorf("%w: invalid API key", protocol.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if account.Username != username {
+		return nil, fmt.Errorf("%w: API key belongs to %s", protocol.ErrUnauthorized, account.Username)
+	}
+
+	player := s.gameManager.NewBotPlayer(account.Username)
+	player.Rating = float64(account.Rating)
+	return player, nil
+}
+
+// botSettings is the body of handleCreateBot
+type botSettings struct {
+	Username string `json:"username"`
+}
+
+func (b *botSettings) Validate() error {
+	if !usernamePattern.MatchString(b.Username) {
+		return errors.New("username must be 3 to 32 letters, digits, '_' or '-'")
+	}
+	return nil
+}
+
+// handleCreateBot registers a bot account and returns its API key. The key
+// cannot be retrieved again, only replaced through handleRotateBotKey.
+func (s *Server) handleCreateBot(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	var data botSettings
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if s.gameManager.IsHostedName(data.Username) {
+		respondAPIError(w, database.ErrUsernameTaken)
+		return
+	}
+
+	key, hash, err := newAPIKey()
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to generate API key")
+		return
+	}
+
+	user, err := s.db.CreateBotAccount(r.Context(), data.Username, hash)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"user":    user,
+		"api_key": key,
+	})
+}
+
+// handleRotateBotKey replaces a bot account's API key
+func (s *Server) handleRotateBotKey(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	key, hash, err := newAPIKey()
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to generate API key")
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+	if err := s.db.SetBotAPIKey(r.Context(), username, hash); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"username": username,
+		"api_key":  key,
+	})
+}
+
+// handleBotLeaderboard ranks bot accounts, which the main leaderboard omits
+func (s *Server) handleBotLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q, ok := s.leaderboardQuery(w, r, 10)
+	if !ok {
+		return
+	}
+
+	users, err := s.db.GetBotLeaderboard(r.Context(), q)
+	if errors.Is(err, database.ErrUserNotFound) {
+		respondError(w, protocol.CodeUserNotFound, fmt.Sprintf("%s is not on the leaderboard", q.After))
+		return
+	}
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to fetch bot leaderboard")
+		return
+	}
+	if users == nil {
+		users = []database.User{}
+	}
+
+	respondJSON(w, http.StatusOK, users)
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"log"
+	"sync"
+
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// maxGameEvents bounds how many past events are kept per game for replay
+const maxGameEvents = 200
+
+// gameEvent is a server message scoped to one game. Seq numbers events in
+// the order they were sent, starting at 1, independently for each game.
+type gameEvent struct {
+	Seq     uint64
+	Type    string
+	Payload interface{}
+	Delta   *protocol.GameDelta // for game_update, see protocol.ServerEnvelope
+}
+
+// eventLog numbers a game's events and keeps the most recent ones so a
+// reconnecting or lagging client can catch up on what it missed
+type eventLog struct {
+	mu      sync.Mutex
+	seq     uint64
+	events  []gameEvent
+	last    *game.Snapshot    // latest state published, to derive the next events
+	acked   map[string]uint64 // playerID -> highest seq acknowledged
+	changed chan struct{}     // closed on the next append, see changedLocked
+}
+
+// appendLocked assigns the next sequence number to an event and records it.
+// The caller must hold l.mu.
+func (l *eventLog) appendLocked(ev gameEvent) gameEvent {
+	l.seq++
+	ev.Seq = l.seq
+	l.events = append(l.events, ev)
+	if len(l.events) > maxGameEvents {
+		l.events = l.events[len(l.events)-maxGameEvents:]
+	}
+	if l.changed != nil {
+		close(l.changed)
+		l.changed = nil
+	}
+	return ev
+}
+
+// changedLocked returns a channel that is closed when the next event is
+// appended, for long-polling clients. The caller must hold l.mu.
+func (l *eventLog) changedLocked() <-chan struct{} {
+	if l.changed == nil {
+		l.changed = make(chan struct{})
+	}
+	return l.changed
+}
+
+// lastSeq returns the sequence number of the latest event. Like ack and
+// ackedSeq it may be called on a nil log, for a game with no events.
+func (l *eventLog) lastSeq() uint64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq
+}
+
+// ack records that a player has received every event up to seq
+func (l *eventLog) ack(playerID string, seq uint64) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq > l.seq {
+		seq = l.seq
+	}
+	if l.acked == nil {
+		l.acked = make(map[string]uint64)
+	}
+	if seq > l.acked[playerID] {
+		l.acked[playerID] = seq
+	}
+}
+
+// ackedSeq returns the last sequence number a player acknowledged, or nil
+func (l *eventLog) ackedSeq(playerID string) *uint64 {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seq, ok := l.acked[playerID]
+	if !ok {
+		return nil
+	}
+	return &seq
+}
+
+// sinceLocked returns the events after seq. ok is false when some of them
+// have already been dropped from the log, in which case the caller must fall
+// back to sending the full state. The caller must hold l.mu.
+func (l *eventLog) sinceLocked(seq uint64) (events []gameEvent, ok bool) {
+	if seq >= l.seq {
+		return nil, true
+	}
+	if len(l.events) == 0 || l.events[0].Seq > seq+1 {
+		return nil, false
+	}
+
+	start := int(seq + 1 - l.events[0].Seq)
+	return append([]gameEvent(nil), l.events[start:]...), true
+}
+
+// gameEventLog returns the event log for a game, creating it on first use
+func (s *Server) gameEventLog(gameID string) *eventLog {
+	s.logsMu.Lock()
+	defer s.logsMu.Unlock()
+
+	l, ok := s.eventLogs[gameID]
+	if !ok {
+		l = &eventLog{}
+		s.eventLogs[gameID] = l
+	}
+	return l
+}
+
+// findGameEventLog returns the event log for a game, or nil if it has none
+func (s *Server) findGameEventLog(gameID string) *eventLog {
+	s.logsMu.Lock()
+	defer s.logsMu.Unlock()
+	return s.eventLogs[gameID]
+}
+
+// dropGameEventLog forgets a game's events once the game leaves memory
+func (s *Server) dropGameEventLog(gameID string) {
+	s.logsMu.Lock()
+	defer s.logsMu.Unlock()
+	delete(s.eventLogs, gameID)
+}
+
+// publishGameEvent records an event in the game's log and sends it to every
+// client in that game. The log stays locked while sending so that clients
+// receive events in sequence order and a client attaching concurrently (see
+// attachToGame) either gets the event live or in its replay, never both.
+func (s *Server) publishGameEvent(gameID, msgType string, payload interface{}) {
+	l := s.gameEventLog(gameID)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s.publishLocked(l, gameID, s.gameClients(gameID), gameEvent{Type: msgType, Payload: payload})
+}
+
+// publishLocked appends an event to l and sends it to clients. The caller
+// must hold l.mu.
+func (s *Server) publishLocked(l *eventLog, gameID string, clients []*WSClient, ev gameEvent) {
+	ev = l.appendLocked(ev)
+	for _, c := range clients {
+		c.sendEvent(ev)
+	}
+
+	log.Printf("Broadcast %s #%d to %d clients for game %s", ev.Type, ev.Seq, len(clients), gameID)
+}
+
+// publishSnapshot publishes the typed events that lead from the previously
+// published state of a game to snap, followed by a game_update carrying the
+// full state
+func (s *Server) publishSnapshot(snap *game.Snapshot) {
+	l := s.gameEventLog(snap.ID)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last != nil && snap.Version <= l.last.Version {
+		return
+	}
+
+	clients := s.gameClients(snap.ID)
+	for _, ev := range snapshotEvents(l.last, snap) {
+		s.publishLocked(l, snap.ID, clients, ev)
+	}
+	s.publishLocked(l, snap.ID, clients, gameEvent{
+		Type:    protocol.TypeGameUpdate,
+		Payload: protocol.NewGameState(snap),
+		Delta:   protocol.NewGameDelta(l.last, snap),
+	})
+	l.last = snap
+}
+
+// snapshotEvents describes what happened between two states of a game as a
+// list of typed events. prev is nil for the first state published.
+func snapshotEvents(prev, cur *game.Snapshot) []gameEvent {
+	var events []gameEvent
+
+	wasStarted := prev != nil && prev.Status != game.StatusWaiting
+	if !wasStarted && cur.Status != game.StatusWaiting && cur.Player2 != nil {
+		events = append(events, gameEvent{Type: protocol.TypeGameStarted, Payload: protocol.GameStarted{
+			Player1:     protocol.NewPlayerState(cur.Player1),
+			Player2:     protocol.NewPlayerState(cur.Player2),
+			CurrentTurn: int(game.Player1),
+		}})
+	}
+
+	seen := 0
+	if prev != nil {
+		seen = len(prev.Moves)
+	}
+	for i, move := range cur.Moves[seen:] {
+		player := cur.PlayerAt(move.Seat)
+		if move.Skipped {
+			events = append(events, gameEvent{Type: protocol.TypeTurnSkipped, Payload: protocol.TurnSkipped{
+				MoveNumber: seen + i + 1,
+				PlayerID:   player.ID,
+				Seat:       int(move.Seat),
+			}})
+			continue
+		}
+		events = append(events, gameEvent{Type: protocol.TypeMoveMade, Payload: protocol.MoveMade{
+			MoveNumber: seen + i + 1,
+			PlayerID:   player.ID,
+			Seat:       int(move.Seat),
+			Column:     move.Column,
+			Row:        move.Row,
+		}})
+	}
+
+	if cur.IsOver() && (prev == nil || !prev.IsOver()) {
+		events = append(events, gameEvent{Type: protocol.TypeGameOver, Payload: protocol.GameOver{
+			Status: string(cur.Status),
+			Result: string(cur.Result),
+			Winner: protocol.NewPlayerState(cur.Winner),
+		}})
+	}
+
+	return events
+}
+
+// attachToGame binds a client to a player and game. When lastSeq is non-nil
+// the client is resuming, and the events it missed since then are replayed;
+// if they are no longer in the log the current game state is sent instead.
+func (s *Server) attachToGame(client *WSClient, playerID string, gameObj *game.Game, lastSeq *uint64) {
+	l := s.gameEventLog(gameObj.ID)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	client.setIDs(playerID, gameObj.ID)
+
+	if lastSeq != nil {
+		s.replayLocked(l, client, gameObj, *lastSeq)
+		return
+	}
+
+	client.sendGameState(gameObj.Snapshot(), l.seq)
+}
+
+// resync replays a game's events after fromSeq to a client that noticed a
+// gap in the sequence numbers it received
+func (s *Server) resync(client *WSClient, gameObj *game.Game, fromSeq uint64) {
+	l := s.gameEventLog(gameObj.ID)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s.replayLocked(l, client, gameObj, fromSeq)
+}
+
+// replayLocked sends the events after fromSeq, or the full current state if
+// they are no longer all in the log. The caller must hold l.mu.
+func (s *Server) replayLocked(l *eventLog, client *WSClient, gameObj *game.Game, fromSeq uint64) {
+	missed, ok := l.sinceLocked(fromSeq)
+	if !ok {
+		client.sendGameState(gameObj.Snapshot(), l.seq)
+		return
+	}
+
+	for _, ev := range missed {
+		client.sendEvent(ev)
+	}
+	log.Printf("Replayed %d events after #%d in game %s", len(missed), fromSeq, gameObj.ID)
+}
+
+// gameClients returns the connected clients currently attached to a game
+func (s *Server) gameClients(gameID string) []*WSClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var clients []*WSClient
+	for c := range s.clients {
+		if _, id := c.ids(); id == gameID {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+// playerClients returns the connected clients bound to a player
+func (s *Server) playerClients(playerID string) []*WSClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var clients []*WSClient
+	for c := range s.clients {
+		if id, _ := c.ids(); id == playerID {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
@@ -0,0 +1,332 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// Fallback transport for clients whose proxies break WebSockets. Players
+// join and act through plain REST calls and receive the same messages a
+// WSClient does, either as a Server-Sent Events stream or by long-polling
+// the game's event log. Requests after join identify the player by the
+// session token from player_info, sent as "Authorization: Bearer <token>"
+// or, for EventSource which cannot set headers, as ?session_token=.
+
+const (
+	ssePingInterval = 15 * time.Second
+	pollTimeout     = 10 * time.Second // below the HTTP server's WriteTimeout
+	writeTimeout    = 10 * time.Second
+)
+
+// handlePlayJoin enters matchmaking, like a WebSocket join
+func (s *Server) handlePlayJoin(w http.ResponseWriter, r *http.Request) {
+	var data protocol.Join
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	player, gameObj, err := s.joinMatchmaking(r.Context(), r.Header.Get(apiKeyHeader), &data)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	log.Printf("Fallback client joined: player_id=%s game_id=%s", player.ID, gameObj.ID)
+
+	respondJSON(w, http.StatusOK, protocol.PlayerInfo{
+		PlayerID:     player.ID,
+		GameID:       gameObj.ID,
+		Username:     player.Username,
+		SessionToken: player.SessionToken,
+	})
+}
+
+// handlePlayMove drops a disc for the session's player
+func (s *Server) handlePlayMove(w http.ResponseWriter, r *http.Request) {
+	gameObj, player, err := s.sessionPlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	var data protocol.Move
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	row, err := s.gameManager.MakeMove(gameObj.ID, player.ID, *data.Column)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"column": *data.Column,
+		"row":    row,
+	})
+}
+
+// handlePlayHeartbeat keeps the session's player connected
+func (s *Server) handlePlayHeartbeat(w http.ResponseWriter, r *http.Request) {
+	gameObj, player, err := s.sessionPlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	s.gameManager.UpdatePlayerHeartbeat(player.ID)
+	respondJSON(w, http.StatusOK, protocol.HeartbeatAck{
+		Seq: s.findGameEventLog(gameObj.ID).lastSeq(),
+	})
+}
+
+// handlePlayCancel leaves matchmaking before an opponent is found, like a
+// WebSocket cancel_search. The session token is no longer valid afterwards.
+func (s *Server) handlePlayCancel(w http.ResponseWriter, r *http.Request) {
+	_, player, err := s.sessionPlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	if err := s.cancelSearch(player.ID); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, protocol.SearchCancelled{Message: "Search cancelled"})
+}
+
+// handlePlayEvents streams the session's game as Server-Sent Events. Each
+// message is sent as an event named after its type with the JSON envelope
+// as data; game events carry their seq as the event id, so a reconnecting
+// EventSource resumes from Last-Event-ID. The stream counts as the
+// player's connection, exactly like a WebSocket.
+func (s *Server) handlePlayEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	if _, ok := w.(http.Flusher); !ok {
+		respondError(w, protocol.CodeInternal, "Streaming unsupported")
+		return
+	}
+
+	lastSeq, err := resumeSeq(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	client := &WSClient{
+		send:    make(chan []byte, 256),
+		server:  s,
+		sse:     true,
+		version: protocol.Version,
+	}
+	s.registerClient(client)
+	defer s.unregisterClient(client)
+
+	if err := s.resumeClient(client, sessionToken(r), lastSeq); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ping := time.NewTicker(ssePingInterval)
+	defer ping.Stop()
+
+	for {
+		var frame []byte
+		select {
+		case message, ok := <-client.send:
+			if !ok {
+				return
+			}
+			frame = message
+		case <-ping.C:
+			frame = []byte(": ping\n\n")
+		case <-r.Context().Done():
+			return
+		}
+
+		rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := w.Write(frame); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// handlePlayPoll returns the session's game events after ?after=, waiting up
+// to pollTimeout for one to arrive. Clients that fell behind the event log
+// get the full state instead. Polling counts as a heartbeat.
+func (s *Server) handlePlayPoll(w http.ResponseWriter, r *http.Request) {
+	gameObj, player, err := s.sessionPlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	var after uint64
+	if raw := r.URL.Query().Get("after"); raw != "" {
+		if after, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			respondAPIError(w, fmt.Errorf("%w: after must be a sequence number", protocol.ErrInvalidMessage))
+			return
+		}
+	}
+
+	s.gameManager.UpdatePlayerHeartbeat(player.ID)
+
+	l := s.gameEventLog(gameObj.ID)
+	timeout := time.NewTimer(pollTimeout)
+	defer timeout.Stop()
+
+	for {
+		l.mu.Lock()
+		missed, ok := l.sinceLocked(after)
+		seq := l.seq
+		changed := l.changedLocked()
+		l.mu.Unlock()
+
+		if !ok {
+			respondPoll(w, seq, []protocol.ServerEnvelope{{
+				Type:    protocol.TypeGameUpdate,
+				Seq:     seq,
+				Payload: protocol.NewGameState(gameObj.Snapshot()),
+			}})
+			return
+		}
+		if len(missed) > 0 {
+			l.ack(player.ID, after)
+			messages := make([]protocol.ServerEnvelope, len(missed))
+			for i, ev := range missed {
+				messages[i] = protocol.ServerEnvelope{Type: ev.Type, Seq: ev.Seq, Payload: ev.Payload}
+			}
+			respondPoll(w, seq, messages)
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timeout.C:
+			respondPoll(w, seq, []protocol.ServerEnvelope{})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func respondPoll(w http.ResponseWriter, seq uint64, messages []protocol.ServerEnvelope) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"seq":      seq,
+		"messages": messages,
+	})
+}
+
+// joinMatchmaking enters matchmaking for a client that is not attached to
+// any connection yet, joining the matched game right away if there is one.
+// An account that is already searching or playing gets that session back.
+func (s *Server) joinMatchmaking(ctx context.Context, apiKey string, join *protocol.Join) (*game.Player, *game.Game, error) {
+	player, err := s.newPlayer(ctx, apiKey, join)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gameObj, matched, err := s.matchmaker.AddPlayer(player, join.Queue)
+	if errors.Is(err, game.ErrAlreadyPlaying) {
+		if token, ok := s.activeSession(apiKey, join, player.Username); ok {
+			gameObj, player, err := s.gameManager.ReconnectPlayer(token)
+			return player, gameObj, err
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if matched {
+		if err := s.matchmaker.JoinMatch(gameObj, player); err != nil {
+			log.Printf("Error joining matched game: %v", err)
+			return nil, nil, game.ErrMatchFailed
+		}
+	}
+	return player, gameObj, nil
+}
+
+// sessionPlayer returns the game and player of the request's session token
+func (s *Server) sessionPlayer(r *http.Request) (*game.Game, *game.Player, error) {
+	token := sessionToken(r)
+	if token == "" {
+		return nil, nil, fmt.Errorf("%w: session token required", protocol.ErrUnauthorized)
+	}
+	return s.gameManager.GetGameBySession(token)
+}
+
+func sessionToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("session_token")
+}
+
+// resumeSeq returns where an SSE stream resumes: the Last-Event-ID sent by
+// a reconnecting EventSource, or ?last_seq=, or nil for the full state
+func resumeSeq(r *http.Request) (*uint64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_seq")
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	seq, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: last event id must be a sequence number", protocol.ErrInvalidMessage)
+	}
+	return &seq, nil
+}
+
+// decodeBody strictly decodes a JSON request body into a protocol payload
+func decodeBody(r *http.Request, payload protocol.ClientPayload) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return fmt.Errorf("%w: %v", protocol.ErrInvalidMessage, err)
+	}
+	if err := payload.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", protocol.ErrInvalidMessage, err)
+	}
+	return nil
+}
+
+// encodeSSE frames a message as a Server-Sent Event
+func encodeSSE(msg protocol.ServerEnvelope) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if msg.Seq != 0 {
+		fmt.Fprintf(&b, "id: %d\n", msg.Seq)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", msg.Type, data)
+	return b.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+)
+
+// REST API for playing games without a WebSocket. Creating or joining a
+// game returns the player's session token; every other call authenticates
+// with it as "Authorization: Bearer <token>" and counts as a heartbeat.
+// Updates reach WebSocket and SSE clients in the game as usual.
+
+// handleCreateGame opens a game and waits for someone to join it by ID.
+// Unlike matchmaking, nobody is paired automatically and no bot steps in.
+func (s *Server) handleCreateGame(w http.ResponseWriter, r *http.Request) {
+	var data protocol.Join
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if s.draining.Load() {
+		respondAPIError(w, game.ErrServerDraining)
+		return
+	}
+
+	player, err := s.newPlayer(r.Context(), r.Header.Get(apiKeyHeader), &data)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	gameObj := s.gameManager.CreateGame(player)
+
+	respondJSON(w, http.StatusCreated, gameSessionResponse(player, gameObj.Snapshot()))
+}
+
+// handleJoinGame takes the second seat of a game created through the API
+func (s *Server) handleJoinGame(w http.ResponseWriter, r *http.Request) {
+	var data protocol.Join
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if s.draining.Load() {
+		respondAPIError(w, game.ErrServerDraining)
+		return
+	}
+
+	player, err := s.newPlayer(r.Context(), r.Header.Get(apiKeyHeader), &data)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if err := s.gameManager.JoinOpenGame(mux.Vars(r)["id"], player); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	gameObj, err := s.gameManager.GetGame(mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, gameSessionResponse(player, gameObj.Snapshot()))
+}
+
+// handleGetGame returns the current state of the caller's game
+func (s *Server) handleGetGame(w http.ResponseWriter, r *http.Request) {
+	gameObj, _, err := s.gamePlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, protocol.NewGameState(gameObj.Snapshot()))
+}
+
+// handleListGames lists the games the caller's username is waiting for or
+// playing, across all of that username's sessions
+func (s *Server) handleListGames(w http.ResponseWriter, r *http.Request) {
+	_, player, err := s.sessionPlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	s.gameManager.UpdatePlayerHeartbeat(player.ID)
+
+	games := []protocol.GameState{}
+	for _, snap := range s.gameManager.ActiveGames(player.Username) {
+		games = append(games, protocol.NewGameState(snap))
+	}
+
+	respondJSON(w, http.StatusOK, games)
+}
+
+// handleGameMove drops a disc for the caller
+func (s *Server) handleGameMove(w http.ResponseWriter, r *http.Request) {
+	gameObj, player, err := s.gamePlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	var data protocol.Move
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	row, err := s.gameManager.MakeMove(gameObj.ID, player.ID, *data.Column)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	snap := gameObj.Snapshot()
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"move": protocol.MoveMade{
+			MoveNumber: moveNumber(snap, player.ID, *data.Column, row),
+			PlayerID:   player.ID,
+			Seat:       int(seatOf(snap, player.ID)),
+			Column:     *data.Column,
+			Row:        row,
+		},
+		"game": protocol.NewGameState(snap),
+	})
+}
+
+// handleResign concedes the caller's game
+func (s *Server) handleResign(w http.ResponseWriter, r *http.Request) {
+	gameObj, player, err := s.gamePlayer(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	// Resigning before an opponent is found only leaves matchmaking, which
+	// discards the waiting game
+	if s.matchmaker.RemovePlayer(player.ID) {
+		s.endSearch(player.ID, "Search cancelled")
+		log.Printf("Player %s left matchmaking via REST", player.Username)
+		respondJSON(w, http.StatusOK, protocol.NewGameState(gameObj.Snapshot()))
+		return
+	}
+	if err := s.gameManager.Resign(gameObj.ID, player.ID); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	log.Printf("Player %s resigned game %s via REST", player.Username, gameObj.ID)
+	respondJSON(w, http.StatusOK, protocol.NewGameState(gameObj.Snapshot()))
+}
+
+// gamePlayer authenticates the caller as a player of the game in the URL
+func (s *Server) gamePlayer(r *http.Request) (*game.Game, *game.Player, error) {
+	gameObj, player, err := s.sessionPlayer(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if gameObj.ID != mux.Vars(r)["id"] {
+		return nil, nil, fmt.Errorf("%w: session does not belong to this game", game.ErrInvalidPlayer)
+	}
+
+	s.gameManager.UpdatePlayerHeartbeat(player.ID)
+	return gameObj, player, nil
+}
+
+func gameSessionResponse(player *game.Player, snap *game.Snapshot) map[string]interface{} {
+	return map[string]interface{}{
+		"player": protocol.PlayerInfo{
+			PlayerID:     player.ID,
+			GameID:       snap.ID,
+			Username:     player.Username,
+			SessionToken: player.SessionToken,
+		},
+		"game": protocol.NewGameState(snap),
+	}
+}
+
+// seatOf returns the seat playerID occupies in snap
+func seatOf(snap *game.Snapshot, playerID string) game.CellState {
+	if snap.Player1 != nil && snap.Player1.ID == playerID {
+		return game.Player1
+	}
+	return game.Player2
+}
+
+// moveNumber finds the move playerID just made in snap. The bot may already
+// have replied, so it is not necessarily the last one.
+func moveNumber(snap *game.Snapshot, playerID string, column, row int) int {
+	seat := seatOf(snap, playerID)
+	for i := len(snap.Moves) - 1; i >= 0; i-- {
+		move := snap.Moves[i]
+		if !move.Skipped && move.Seat == seat && move.Column == column && move.Row == row {
+			return i + 1
+		}
+	}
+	return len(snap.Moves)
+}
@@ -0,0 +1,331 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+	"github.com/yourusername/4-in-a-row/internal/protocol/pb"
+)
+
+// apiKeyMetadata carries a bot account's API key, like the X-API-Key header
+const apiKeyMetadata = "x-api-key"
+
+// grpcService implements pb.GameServiceServer on top of the same manager,
+// matchmaker and event fan-out as the WebSocket and REST APIs
+type grpcService struct {
+	pb.UnimplementedGameServiceServer
+	server *Server
+}
+
+// GRPCServer returns a gRPC server exposing the game service
+func (s *Server) GRPCServer() *grpc.Server {
+	srv := grpc.NewServer()
+	pb.RegisterGameServiceServer(srv, &grpcService{server: s})
+	return srv
+}
+
+func (g *grpcService) FindMatch(ctx context.Context, req *pb.FindMatchRequest) (*pb.GameSession, error) {
+	join, err := grpcJoin(req.GetUsername(), req.GetToken(), req.GetGuest())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	join.Queue = req.GetQueue()
+
+	player, gameObj, err := g.server.joinMatchmaking(ctx, metadataValue(ctx, apiKeyMetadata), join)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return grpcSession(player, gameObj.Snapshot()), nil
+}
+
+func (g *grpcService) CreateGame(ctx context.Context, req *pb.CreateGameRequest) (*pb.GameSession, error) {
+	join, err := grpcJoin(req.GetUsername(), req.GetToken(), req.GetGuest())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if g.server.draining.Load() {
+		return nil, grpcError(game.ErrServerDraining)
+	}
+
+	player, err := g.server.newPlayer(ctx, metadataValue(ctx, apiKeyMetadata), join)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	gameObj := g.server.gameManager.CreateGame(player)
+	return grpcSession(player, gameObj.Snapshot()), nil
+}
+
+func (g *grpcService) JoinGame(ctx context.Context, req *pb.JoinGameRequest) (*pb.GameSession, error) {
+	join, err := grpcJoin(req.GetUsername(), req.GetToken(), req.GetGuest())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if g.server.draining.Load() {
+		return nil, grpcError(game.ErrServerDraining)
+	}
+
+	player, err := g.server.newPlayer(ctx, metadataValue(ctx, apiKeyMetadata), join)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := g.server.gameManager.JoinOpenGame(req.GetGameId(), player); err != nil {
+		return nil, grpcError(err)
+	}
+	gameObj, err := g.server.gameManager.GetGame(req.GetGameId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return grpcSession(player, gameObj.Snapshot()), nil
+}
+
+func (g *grpcService) GetGame(ctx context.Context, req *pb.GetGameRequest) (*pb.GameState, error) {
+	gameObj, _, err := g.sessionPlayer(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return pbMessage[*pb.GameState](protocol.ServerEnvelope{
+		Type:    protocol.TypeGameUpdate,
+		Payload: protocol.NewGameState(gameObj.Snapshot()),
+	})
+}
+
+// WatchGame registers the stream as a client of the caller's game, like an
+// SSE stream, and forwards everything it is sent
+func (g *grpcService) WatchGame(req *pb.WatchGameRequest, stream pb.GameService_WatchGameServer) error {
+	s := g.server
+	client := &WSClient{
+		send:    make(chan []byte, 256),
+		server:  s,
+		binary:  true,
+		version: protocol.Version,
+	}
+	s.registerClient(client)
+	defer s.unregisterClient(client)
+
+	if err := s.resumeClient(client, bearerToken(stream.Context()), req.LastSeq); err != nil {
+		return grpcError(err)
+	}
+
+	for {
+		select {
+		case data, ok := <-client.send:
+			if !ok {
+				return status.Error(codes.Aborted, "stream closed, resume from the last seq received")
+			}
+			var msg pb.ServerMessage
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				return status.Error(codes.Internal, "failed to encode message")
+			}
+			if err := stream.Send(&msg); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (g *grpcService) MakeMove(ctx context.Context, req *pb.MakeMoveRequest) (*pb.MoveMade, error) {
+	gameObj, player, err := g.sessionPlayer(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	column := int(req.GetColumn())
+	row, err := g.server.gameManager.MakeMove(gameObj.ID, player.ID, column)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	snap := gameObj.Snapshot()
+	return &pb.MoveMade{
+		MoveNumber: int32(moveNumber(snap, player.ID, column, row)),
+		PlayerId:   player.ID,
+		Seat:       int32(seatOf(snap, player.ID)),
+		Column:     int32(column),
+		Row:        int32(row),
+	}, nil
+}
+
+func (g *grpcService) Resign(ctx context.Context, req *pb.ResignRequest) (*pb.GameState, error) {
+	gameObj, player, err := g.sessionPlayer(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	// Resigning before an opponent is found only leaves matchmaking
+	if g.server.matchmaker.RemovePlayer(player.ID) {
+		g.server.endSearch(player.ID, "Search cancelled")
+		return pbMessage[*pb.GameState](protocol.ServerEnvelope{
+			Type:    protocol.TypeGameUpdate,
+			Payload: protocol.NewGameState(gameObj.Snapshot()),
+		})
+	}
+	if err := g.server.gameManager.Resign(gameObj.ID, player.ID); err != nil {
+		return nil, grpcError(err)
+	}
+	return pbMessage[*pb.GameState](protocol.ServerEnvelope{
+		Type:    protocol.TypeGameUpdate,
+		Payload: protocol.NewGameState(gameObj.Snapshot()),
+	})
+}
+
+func (g *grpcService) AnalyzePosition(ctx context.Context, req *pb.AnalyzePositionRequest) (*pb.AnalyzePositionResponse, error) {
+	moves := make([]int, len(req.GetMoves()))
+	for i, column := range req.GetMoves() {
+		moves[i] = int(column)
+	}
+
+	analysis, err := game.AnalyzeMoves(moves)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.AnalyzePositionResponse{
+		ToMove:     int32(analysis.ToMove),
+		BestColumn: int32(analysis.BestColumn),
+	}
+	for column := 0; column < game.Columns; column++ {
+		if score, ok := analysis.Scores[column]; ok {
+			resp.Scores = append(resp.Scores, &pb.ColumnScore{Column: int32(column), Score: score})
+		}
+	}
+	return resp, nil
+}
+
+// sessionPlayer authenticates the call by its bearer session token and
+// counts it as a heartbeat
+func (g *grpcService) sessionPlayer(ctx context.Context) (*game.Game, *game.Player, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		return nil, nil, fmt.Errorf("%w: session token required", protocol.ErrUnauthorized)
+	}
+
+	gameObj, player, err := g.server.gameManager.GetGameBySession(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	g.server.gameManager.UpdatePlayerHeartbeat(player.ID)
+	return gameObj, player, nil
+}
+
+// grpcJoin builds and validates the join payload equivalent to a request
+func grpcJoin(username, token string, guest bool) (*protocol.Join, error) {
+	join := &protocol.Join{Username: username, Token: token, Guest: guest}
+	if err := join.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", protocol.ErrInvalidMessage, err)
+	}
+	return join, nil
+}
+
+func bearerToken(ctx context.Context) string {
+	auth := metadataValue(ctx, "authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(auth, "Bearer ")
+}
+
+// metadataValue returns the first value of an incoming metadata key
+func metadataValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func grpcSession(player *game.Player, snap *game.Snapshot) *pb.GameSession {
+	session := &pb.GameSession{
+		Player: &pb.PlayerInfo{
+			PlayerId:     player.ID,
+			GameId:       snap.ID,
+			Username:     player.Username,
+			SessionToken: player.SessionToken,
+		},
+	}
+	session.Game, _ = pbMessage[*pb.GameState](protocol.ServerEnvelope{
+		Type:    protocol.TypeGameUpdate,
+		Payload: protocol.NewGameState(snap),
+	})
+	return session
+}
+
+// pbMessage converts a server message to its protobuf form through the
+// subprotocol encoding, so both APIs stay identical
+func pbMessage[T proto.Message](env protocol.ServerEnvelope) (T, error) {
+	var zero T
+	data, err := protocol.EncodeBinary(env)
+	if err != nil {
+		return zero, status.Error(codes.Internal, "failed to encode message")
+	}
+	var msg pb.ServerMessage
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return zero, status.Error(codes.Internal, "failed to encode message")
+	}
+
+	var inner proto.Message
+	switch m := msg.Msg.(type) {
+	case *pb.ServerMessage_GameUpdate:
+		inner = m.GameUpdate
+	case *pb.ServerMessage_MoveMade:
+		inner = m.MoveMade
+	}
+	out, ok := inner.(T)
+	if !ok {
+		return zero, status.Error(codes.Internal, "unexpected message type")
+	}
+	return out, nil
+}
+
+// grpcCodes maps the HTTP statuses of protocol's error catalogue to gRPC
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusGone:                codes.FailedPrecondition,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// grpcError converts err to a gRPC status carrying its catalogue code as
+// the reason of an ErrorInfo detail
+func grpcError(err error) error {
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcStatus) {
+		return err
+	}
+
+	payload := protocol.NewError(err)
+	if payload.Code == protocol.CodeInternal {
+		log.Printf("Internal error: %v", err)
+	}
+
+	code, ok := grpcCodes[payload.Code.HTTPStatus()]
+	if !ok {
+		code = codes.Unknown
+	}
+	st, detailErr := status.New(code, payload.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(payload.Code),
+		Domain: "4inarow",
+	})
+	if detailErr != nil {
+		return status.Error(code, payload.Message)
+	}
+	return st.Err()
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+)
+
+// Seasons are set in the configuration. Their live leaderboards are the
+// regular ones with ?season=; once a season ends its final standings are
+// archived and served here.
+
+// seasonDetails is a season with its archived standings, empty until it
+// has ended and been archived
+type seasonDetails struct {
+	database.Season
+	Players []database.SeasonStanding `json:"players"`
+	Bots    []database.SeasonStanding `json:"bots"`
+}
+
+// handleListSeasons lists every season, the earliest first
+func (s *Server) handleListSeasons(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.seasons.List())
+}
+
+// handleGetSeason returns a season, or the one running now for "current",
+// with its archived standings
+func (s *Server) handleGetSeason(w http.ResponseWriter, r *http.Request) {
+	found, err := s.seasons.Find(mux.Vars(r)["name"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	details := seasonDetails{Season: found}
+	if details.Players, err = s.db.GetSeasonStandings(r.Context(), found.Name, false); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if details.Bots, err = s.db.GetSeasonStandings(r.Context(), found.Name, true); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if details.Players == nil {
+		details.Players = []database.SeasonStanding{}
+	}
+	if details.Bots == nil {
+		details.Bots = []database.SeasonStanding{}
+	}
+
+	respondJSON(w, http.StatusOK, details)
+}
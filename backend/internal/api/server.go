@@ -1,17 +1,29 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+	"github.com/yourusername/4-in-a-row/internal/arena"
+	"github.com/yourusername/4-in-a-row/internal/auth"
+	"github.com/yourusername/4-in-a-row/internal/clock"
 	"github.com/yourusername/4-in-a-row/internal/config"
 	"github.com/yourusername/4-in-a-row/internal/database"
 	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
+	"github.com/yourusername/4-in-a-row/internal/season"
+	"github.com/yourusername/4-in-a-row/internal/tournament"
 )
 
 type Server struct {
@@ -19,24 +31,50 @@ type Server struct {
 	gameManager *game.Manager
 	matchmaker  *game.Matchmaker
 	db          *database.DB
+	auth        *auth.Issuer
+	tournaments *tournament.Director
+	arenas      *arena.Director
+	seasons     *season.Keeper
+	clock       clock.Clock
 	clients     map[*WSClient]bool
 	mu          sync.RWMutex
+
+	eventLogs map[string]*eventLog // gameID -> recent events, see events.go
+	logsMu    sync.Mutex
+
+	draining       atomic.Bool
+	drainRequested chan struct{}
+	drainOnce      sync.Once
 }
 
-func NewServer(cfg *config.Config, gameManager *game.Manager, matchmaker *game.Matchmaker, db *database.DB) *Server {
+func NewServer(cfg *config.Config, gameManager *game.Manager, matchmaker *game.Matchmaker, db *database.DB, issuer *auth.Issuer, tournaments *tournament.Director, arenas *arena.Director, seasons *season.Keeper, clk clock.Clock) *Server {
 	s := &Server{
 		config:      cfg,
 		gameManager: gameManager,
 		matchmaker:  matchmaker,
 		db:          db,
+		auth:        issuer,
+		tournaments: tournaments,
+		arenas:      arenas,
+		seasons:     seasons,
+		clock:       clk,
 		clients:     make(map[*WSClient]bool),
+		eventLogs:   make(map[string]*eventLog),
+
+		drainRequested: make(chan struct{}),
 	}
 
 	// Register callback to broadcast game updates when state changes (e.g., bot joins)
-	gameManager.SetGameUpdateCallback(func(gameID string) {
-		log.Printf("GameUpdateCallback invoked for game %s", gameID)
-		s.broadcastGameUpdate(gameID)
+	gameManager.SetGameUpdateCallback(func(snap *game.Snapshot) {
+		log.Printf("GameUpdateCallback invoked for game %s", snap.ID)
+		s.broadcastGameUpdate(snap)
 	})
+	gameManager.SetGameRemovedCallback(s.dropGameEventLog)
+	matchmaker.SetMatchCallback(s.followMatch)
+	matchmaker.SetQueueStatusCallback(s.sendQueueStatus)
+	arenas.SetLeaderboardCallback(s.sendArenaLeaderboard)
+	arenas.SetRequeueCallback(s.followRequeue)
+	arenas.SetClosedCallback(s.closeArenaSearches)
 
 	return s
 }
@@ -50,12 +88,60 @@ func (s *Server) Router() http.Handler {
 	// REST API endpoints
 	api := r.PathPrefix("/api").Subrouter()
 	api.HandleFunc("/health", s.handleHealth).Methods("GET")
+	api.HandleFunc("/protocol/schema", s.handleProtocolSchema).Methods("GET")
+	api.HandleFunc("/auth/register", s.handleRegister).Methods("POST")
+	api.HandleFunc("/auth/login", s.handleLogin).Methods("POST")
+	api.HandleFunc("/auth/me", s.handleMe).Methods("GET")
+	api.HandleFunc("/queues", s.handleQueues).Methods("GET")
 	api.HandleFunc("/leaderboard", s.handleLeaderboard).Methods("GET")
+	api.HandleFunc("/leaderboard/bots", s.handleBotLeaderboard).Methods("GET")
+	api.HandleFunc("/leaderboard/around/{username}", s.handleLeaderboardAround).Methods("GET")
 	api.HandleFunc("/user/{username}", s.handleUserStats).Methods("GET")
+	api.HandleFunc("/user/{username}/ratings", s.handleRatingHistory).Methods("GET")
 	api.HandleFunc("/games/recent", s.handleRecentGames).Methods("GET")
 	api.HandleFunc("/games/user/{username}", s.handleUserGames).Methods("GET")
 	api.HandleFunc("/analytics/hourly", s.handleHourlyAnalytics).Methods("GET")
 	api.HandleFunc("/analytics/daily", s.handleDailyAnalytics).Methods("GET")
+	api.HandleFunc("/admin/drain", s.handleDrain).Methods("POST")
+	api.HandleFunc("/admin/bots", s.handleCreateBot).Methods("POST")
+	api.HandleFunc("/admin/bots/{username}/key", s.handleRotateBotKey).Methods("POST")
+	api.HandleFunc("/admin/tournaments", s.handleCreateTournament).Methods("POST")
+	api.HandleFunc("/admin/tournaments/{id}/start", s.handleStartTournament).Methods("POST")
+	api.HandleFunc("/admin/arenas", s.handleCreateArena).Methods("POST")
+
+	// Tournaments, see tournaments.go
+	api.HandleFunc("/tournaments", s.handleListTournaments).Methods("GET")
+	api.HandleFunc("/tournaments/{id}", s.handleGetTournament).Methods("GET")
+	api.HandleFunc("/tournaments/{id}/standings", s.handleTournamentStandings).Methods("GET")
+	api.HandleFunc("/tournaments/{id}/bracket", s.handleTournamentBracket).Methods("GET")
+	api.HandleFunc("/tournaments/{id}/players", s.handleRegisterTournament).Methods("POST")
+	api.HandleFunc("/tournaments/{id}/players", s.handleWithdrawTournament).Methods("DELETE")
+	api.HandleFunc("/tournaments/{id}/session", s.handleTournamentSession).Methods("GET")
+
+	// Arenas, see arenas.go
+	api.HandleFunc("/arenas", s.handleListArenas).Methods("GET")
+	api.HandleFunc("/arenas/{id}", s.handleGetArena).Methods("GET")
+
+	// Seasons, see seasons.go
+	api.HandleFunc("/seasons", s.handleListSeasons).Methods("GET")
+	api.HandleFunc("/seasons/{name}", s.handleGetSeason).Methods("GET")
+
+	// Fallback transport for clients that cannot use WebSockets, see fallback.go
+	api.HandleFunc("/play/join", s.handlePlayJoin).Methods("POST")
+	api.HandleFunc("/play/move", s.handlePlayMove).Methods("POST")
+	api.HandleFunc("/play/heartbeat", s.handlePlayHeartbeat).Methods("POST")
+	api.HandleFunc("/play/cancel", s.handlePlayCancel).Methods("POST")
+	api.HandleFunc("/play/events", s.handlePlayEvents).Methods("GET")
+	api.HandleFunc("/play/poll", s.handlePlayPoll).Methods("GET")
+
+	// Gameplay over REST, see games.go. Registered after /games/recent and
+	// /games/user/{username} so those keep matching first.
+	api.HandleFunc("/games", s.handleCreateGame).Methods("POST")
+	api.HandleFunc("/games", s.handleListGames).Methods("GET")
+	api.HandleFunc("/games/{id}", s.handleGetGame).Methods("GET")
+	api.HandleFunc("/games/{id}/join", s.handleJoinGame).Methods("POST")
+	api.HandleFunc("/games/{id}/moves", s.handleGameMove).Methods("POST")
+	api.HandleFunc("/games/{id}/resign", s.handleResign).Methods("POST")
 
 	// CORS middleware
 	c := cors.New(cors.Options{
@@ -69,23 +155,77 @@ func (s *Server) Router() http.Handler {
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	// Report draining as unhealthy so load balancers stop routing new players here
+	if s.draining.Load() {
+		respondJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status":    "draining",
+			"timestamp": "ok",
+		})
+		return
+	}
+
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"status":    "healthy",
 		"timestamp": "ok",
 	})
 }
 
+// handleProtocolSchema publishes the JSON Schema of the WebSocket protocol
+func (s *Server) handleProtocolSchema(w http.ResponseWriter, r *http.Request) {
+	data, err := protocol.SchemaJSON()
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to generate schema")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(data)
+}
+
+// handleQueues lists the matchmaking queues with how many players are
+// waiting in each and how long recent players waited
+func (s *Server) handleQueues(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.matchmaker.QueueStats())
+}
+
 func (s *Server) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
-	limit := 10
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
+	q, ok := s.leaderboardQuery(w, r, 10)
+	if !ok {
+		return
 	}
 
-	users, err := s.db.GetLeaderboard(r.Context(), limit)
+	users, err := s.db.GetLeaderboard(r.Context(), q)
+	if errors.Is(err, database.ErrUserNotFound) {
+		respondError(w, protocol.CodeUserNotFound, fmt.Sprintf("%s is not on the leaderboard", q.After))
+		return
+	}
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch leaderboard")
+		respondError(w, protocol.CodeInternal, "Failed to fetch leaderboard")
+		return
+	}
+	if users == nil {
+		users = []database.User{}
+	}
+
+	respondJSON(w, http.StatusOK, users)
+}
+
+// handleLeaderboardAround returns a player's place on the leaderboard with
+// the players just above and below them, limit on each side
+func (s *Server) handleLeaderboardAround(w http.ResponseWriter, r *http.Request) {
+	q, ok := s.leaderboardQuery(w, r, 5)
+	if !ok {
+		return
+	}
+
+	username := mux.Vars(r)["username"]
+	users, err := s.db.GetLeaderboardAround(r.Context(), q, username)
+	if errors.Is(err, database.ErrUserNotFound) {
+		respondError(w, protocol.CodeUserNotFound, fmt.Sprintf("%s is not on the leaderboard", username))
+		return
+	}
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to fetch leaderboard")
 		return
 	}
 
@@ -97,14 +237,107 @@ func (s *Server) handleUserStats(w http.ResponseWriter, r *http.Request) {
 	username := vars["username"]
 
 	user, err := s.db.GetUserStats(r.Context(), username)
+	if errors.Is(err, database.ErrUserNotFound) {
+		respondError(w, protocol.CodeUserNotFound, "User not found")
+		return
+	}
 	if err != nil {
-		respondError(w, http.StatusNotFound, "User not found")
+		respondError(w, protocol.CodeInternal, "Failed to fetch user")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, user)
 }
 
+// handleRatingHistory lists how a player's recent games moved their rating
+func (s *Server) handleRatingHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = min(l, maxListLimit)
+		}
+	}
+
+	changes, err := s.db.GetRatingHistory(r.Context(), username, limit)
+	if err != nil {
+		respondError(w, protocol.CodeInternal, "Failed to fetch rating history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, changes)
+}
+
+// leaderboardSort reads the sort query parameter, which defaults to wins.
+// It responds with an error and returns false for an unknown ordering.
+func leaderboardSort(w http.ResponseWriter, r *http.Request) (string, bool) {
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		return database.SortByWins, true
+	}
+	if !database.IsLeaderboardSort(sortBy) {
+		respondError(w, protocol.CodeInvalidMessage, fmt.Sprintf("Unknown sort %q", sortBy))
+		return "", false
+	}
+	return sortBy, true
+}
+
+// maxListLimit caps how many rows one leaderboard or rating history request
+// returns
+const maxListLimit = 100
+
+// leaderboardQuery reads the query parameters a leaderboard takes: limit,
+// which defaults to limit and is capped at maxListLimit; sort; season and
+// period, which default to all time; the min_games and active_days
+// filters; and after, the cursor. It responds with an error and returns
+// false for an invalid one.
+func (s *Server) leaderboardQuery(w http.ResponseWriter, r *http.Request, limit int) (database.LeaderboardQuery, bool) {
+	query := r.URL.Query()
+	q := database.LeaderboardQuery{Limit: limit, After: query.Get("after")}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			q.Limit = min(l, maxListLimit)
+		}
+	}
+
+	var ok bool
+	if q.SortBy, ok = leaderboardSort(w, r); !ok {
+		return q, false
+	}
+
+	period, ok := season.ParsePeriod(query.Get("period"))
+	if !ok {
+		respondError(w, protocol.CodeInvalidMessage, fmt.Sprintf("Unknown period %q", query.Get("period")))
+		return q, false
+	}
+	scope, err := s.seasons.Scope(query.Get("season"), period)
+	if err != nil {
+		respondAPIError(w, err)
+		return q, false
+	}
+	q.LeaderboardScope = scope
+
+	if v := query.Get("min_games"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			respondError(w, protocol.CodeInvalidMessage, "min_games must be a number of games")
+			return q, false
+		}
+		q.MinGames = n
+	}
+	if v := query.Get("active_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			respondError(w, protocol.CodeInvalidMessage, "active_days must be a positive number of days")
+			return q, false
+		}
+		q.ActiveSince = s.clock.Now().AddDate(0, 0, -days)
+	}
+	return q, true
+}
+
 func (s *Server) handleRecentGames(w http.ResponseWriter, r *http.Request) {
 	limit := 20
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -115,7 +348,7 @@ func (s *Server) handleRecentGames(w http.ResponseWriter, r *http.Request) {
 
 	games, err := s.db.GetRecentGames(r.Context(), limit)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch games")
+		respondError(w, protocol.CodeInternal, "Failed to fetch games")
 		return
 	}
 
@@ -135,7 +368,7 @@ func (s *Server) handleUserGames(w http.ResponseWriter, r *http.Request) {
 
 	games, err := s.db.GetUserGames(r.Context(), username, limit)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch user games")
+		respondError(w, protocol.CodeInternal, "Failed to fetch user games")
 		return
 	}
 
@@ -152,7 +385,7 @@ func (s *Server) handleHourlyAnalytics(w http.ResponseWriter, r *http.Request) {
 
 	analytics, err := s.db.GetHourlyAnalytics(r.Context(), hours)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch hourly analytics")
+		respondError(w, protocol.CodeInternal, "Failed to fetch hourly analytics")
 		return
 	}
 
@@ -169,53 +402,125 @@ func (s *Server) handleDailyAnalytics(w http.ResponseWriter, r *http.Request) {
 
 	analytics, err := s.db.GetDailyAnalytics(r.Context(), days)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to fetch daily analytics")
+		respondError(w, protocol.CodeInternal, "Failed to fetch daily analytics")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, analytics)
 }
 
-func respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+// handleDrain lets an operator put the server into drain mode ahead of a
+// deploy. It requires the configured admin token and is disabled without one.
+func (s *Server) handleDrain(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	s.RequestDrain()
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":        "draining",
+		"drain_timeout": s.config.DrainTimeout.Seconds(),
+	})
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+// checkAdmin authenticates an admin API request, responding with an error
+// and returning false if it does not carry the configured admin token
+func (s *Server) checkAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.config.AdminToken == "" {
+		respondError(w, protocol.CodeAdminDisabled, "Admin API disabled")
+		return false
+	}
+	given, expected := []byte(r.Header.Get("Authorization")), []byte("Bearer "+s.config.AdminToken)
+	if subtle.ConstantTimeCompare(given, expected) != 1 {
+		respondError(w, protocol.CodeUnauthorized, "Invalid admin token")
+		return false
+	}
+	return true
 }
 
-// broadcastGameUpdate sends game state update to all connected clients in a game
-func (s *Server) broadcastGameUpdate(gameID string) {
-	log.Printf("Broadcasting game update for game: %s", gameID)
+// RequestDrain asks main to start draining the server; see DrainRequested
+func (s *Server) RequestDrain() {
+	s.drainOnce.Do(func() {
+		close(s.drainRequested)
+	})
+}
 
-	gameObj, err := s.gameManager.GetGame(gameID)
-	if err != nil {
-		log.Printf("Error getting game %s: %v", gameID, err)
-		return
-	}
+// DrainRequested is closed once a drain has been requested through the admin API
+func (s *Server) DrainRequested() <-chan struct{} {
+	return s.drainRequested
+}
 
-	gameData, err := gameObj.ToJSON()
-	if err != nil {
-		log.Printf("Error converting game to JSON: %v", err)
-		return
-	}
+// Drain stops matchmaking, warns every connected client, ends the searches
+// of players still waiting and waits up to timeout for active games to
+// finish. Games still running at the deadline are abandoned and saved.
+func (s *Server) Drain(timeout time.Duration) {
+	s.draining.Store(true)
+	deadline := s.clock.Now().Add(timeout)
 
-	var gameMap map[string]interface{}
-	json.Unmarshal(gameData, &gameMap)
+	waiting := s.matchmaker.Drain()
 
-	// Broadcast to all clients in this game
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	clientCount := 0
+	clients := make([]*WSClient, 0, len(s.clients))
 	for c := range s.clients {
-		if c.gameID == gameID {
-			clientCount++
-			c.sendMessage("game_update", gameMap)
+		clients = append(clients, c)
+	}
+	s.mu.RUnlock()
+
+	for _, c := range clients {
+		c.sendMessage(protocol.TypeServerDraining, protocol.ServerDraining{
+			Message:  "Server is restarting. Current games may finish; new games are paused.",
+			Deadline: deadline,
+		})
+	}
+	for _, player := range waiting {
+		s.endSearch(player.ID, "Server is restarting")
+	}
+
+	log.Printf("Draining: waiting up to %v for %d active games", timeout, s.gameManager.InProgressCount())
+
+	// The deadline is kept by the server's clock, like every other timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.clock.After(timeout):
+			cancel()
+		case <-ctx.Done():
 		}
+	}()
+
+	if abandoned := s.gameManager.WaitForGames(ctx); abandoned > 0 {
+		log.Printf("Drain deadline reached: abandoned %d games", abandoned)
+	} else {
+		log.Println("Drain complete: all games finished")
 	}
+}
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// respondAPIError writes err with the code and status from protocol's error
+// catalogue
+func respondAPIError(w http.ResponseWriter, err error) {
+	payload := protocol.NewError(err)
+	if payload.Code == protocol.CodeInternal {
+		log.Printf("Internal error: %v", err)
+	}
+	respondError(w, payload.Code, payload.Message)
+}
+
+// respondError writes an error body with a code from protocol's catalogue,
+// using the HTTP status that goes with the code
+func respondError(w http.ResponseWriter, code protocol.ErrorCode, message string) {
+	respondJSON(w, code.HTTPStatus(), map[string]string{"error": message, "code": string(code)})
+}
 
-	log.Printf("Broadcast game_update to %d clients for game %s", clientCount, gameID)
+// broadcastGameUpdate sends the events describing a game's new state to all
+// connected clients in the game
+func (s *Server) broadcastGameUpdate(snap *game.Snapshot) {
+	log.Printf("Broadcasting game update for game: %s", snap.ID)
+	s.publishSnapshot(snap)
 }
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/tournament"
+)
+
+// Tournaments are created and started by an operator through the admin API.
+// Accounts and bots register themselves with their token or API key, then
+// fetch the session of each game they are due to play from /session and
+// play it by reconnecting with that session token.
+
+// tournamentSettings is the body of handleCreateTournament
+type tournamentSettings struct {
+	Name   string            `json:"name"`
+	Format tournament.Format `json:"format"`
+	Rounds int               `json:"rounds,omitempty"`  // Swiss only, 0 picks a number from the players
+	BestOf int               `json:"best_of,omitempty"` // knockouts only, games per match, 0 for 1
+	Rated  bool              `json:"rated"`
+}
+
+func (t *tournamentSettings) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" || len(t.Name) > 64 {
+		return errors.New("name must be 1 to 64 characters")
+	}
+	if !t.Format.IsValid() {
+		return errors.New("format must be swiss, round_robin, single_elimination or double_elimination")
+	}
+	if t.Rounds < 0 || t.Rounds > 50 {
+		return errors.New("rounds must be 0 to 50")
+	}
+	// An even number of games could leave a match tied
+	if t.BestOf < 0 || t.BestOf > 9 || (t.BestOf > 0 && t.BestOf%2 == 0) {
+		return errors.New("best_of must be 1, 3, 5, 7 or 9")
+	}
+	return nil
+}
+
+// handleListTournaments lists recent tournaments, optionally only those
+// with ?status=
+func (s *Server) handleListTournaments(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	tournaments, err := s.tournaments.List(r.Context(), limit, r.URL.Query().Get("status"))
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if tournaments == nil {
+		tournaments = []database.Tournament{}
+	}
+
+	respondJSON(w, http.StatusOK, tournaments)
+}
+
+// handleGetTournament returns a tournament with its players, pairings and
+// standings
+func (s *Server) handleGetTournament(w http.ResponseWriter, r *http.Request) {
+	t, err := s.tournaments.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, t)
+}
+
+// handleTournamentStandings returns just a tournament's standings, which
+// knockouts do not have
+func (s *Server) handleTournamentStandings(w http.ResponseWriter, r *http.Request) {
+	t, err := s.tournaments.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+	if t.Standings == nil {
+		t.Standings = []tournament.Standing{}
+	}
+
+	respondJSON(w, http.StatusOK, t.Standings)
+}
+
+// handleTournamentBracket returns a knockout's bracket, for clients to draw
+func (s *Server) handleTournamentBracket(w http.ResponseWriter, r *http.Request) {
+	bracket, err := s.tournaments.Bracket(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bracket)
+}
+
+// handleRegisterTournament signs the caller's account up for a tournament
+func (s *Server) handleRegisterTournament(w http.ResponseWriter, r *http.Request) {
+	user, err := s.requestAccount(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	player, err := s.tournaments.Register(r.Context(), mux.Vars(r)["id"], user)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, player)
+}
+
+// handleWithdrawTournament takes the caller's account off a tournament
+// that has not started
+func (s *Server) handleWithdrawTournament(w http.ResponseWriter, r *http.Request) {
+	user, err := s.requestAccount(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	if err := s.tournaments.Withdraw(r.Context(), mux.Vars(r)["id"], user.Username); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTournamentSession returns the session of the caller's game in the
+// current round, to play it with a reconnect
+func (s *Server) handleTournamentSession(w http.ResponseWriter, r *http.Request) {
+	user, err := s.requestAccount(r)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	gameObj, player, err := s.tournaments.CurrentGame(mux.Vars(r)["id"], user.Username)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, gameSessionResponse(player, gameObj.Snapshot()))
+}
+
+// handleCreateTournament opens a tournament for registration
+func (s *Server) handleCreateTournament(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	var data tournamentSettings
+	if err := decodeBody(r, &data); err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	t, err := s.tournaments.Create(r.Context(), data.Name, data.Format, data.Rounds, data.BestOf, data.Rated)
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, t)
+}
+
+// handleStartTournament closes registration and starts the first round
+func (s *Server) handleStartTournament(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if s.draining.Load() {
+		respondAPIError(w, game.ErrServerDraining)
+		return
+	}
+
+	t, err := s.tournaments.Start(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondAPIError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, t)
+}
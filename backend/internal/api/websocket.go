@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,17 +12,14 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol"
 )
 
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins for development
 	},
-}
-
-type WSMessage struct {
-	Type    string          `json:"type"`
-	Payload json.RawMessage `json:"payload"`
+	Subprotocols: []string{protocol.SubprotocolProtobuf, protocol.SubprotocolJSON},
 }
 
 type WSClient struct {
@@ -28,8 +27,35 @@ type WSClient struct {
 	playerID string
 	gameID   string
 	send     chan []byte
+	closed   bool   // set once send has been closed
+	closeMsg []byte // close frame to send once the queue drains, if any
 	server   *Server
-	mu       sync.Mutex
+	binary   bool       // speaks the protobuf subprotocol
+	apiKey   string     // bot account key sent with the upgrade request, see bots.go
+	sse      bool       // an SSE stream rather than a WebSocket, see fallback.go
+	mu       sync.Mutex // guards playerID, gameID, closed and closeMsg
+
+	// Only touched by readPump
+	version   int  // negotiated protocol version
+	handshake bool // set once any message has been handled
+}
+
+// closeCodeResync tells a client that it fell too far behind and should
+// reconnect with its last seen sequence number to catch up
+const closeCodeResync = 4000
+
+// ids returns the player and game this client is bound to
+func (client *WSClient) ids() (playerID, gameID string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.playerID, client.gameID
+}
+
+func (client *WSClient) setIDs(playerID, gameID string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.playerID = playerID
+	client.gameID = gameID
 }
 
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -39,9 +65,12 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &WSClient{
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		server: s,
+		conn:    conn,
+		send:    make(chan []byte, 256),
+		server:  s,
+		binary:  conn.Subprotocol() == protocol.SubprotocolProtobuf,
+		apiKey:  r.Header.Get(apiKeyHeader),
+		version: protocol.Version,
 	}
 
 	s.registerClient(client)
@@ -64,19 +93,52 @@ func (s *Server) registerClient(client *WSClient) {
 
 func (s *Server) unregisterClient(client *WSClient) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	_, ok := s.clients[client]
+	delete(s.clients, client)
+	s.mu.Unlock()
 
-	if _, ok := s.clients[client]; ok {
-		delete(s.clients, client)
+	if !ok {
+		return
+	}
+
+	client.mu.Lock()
+	if !client.closed {
+		client.closed = true
 		close(client.send)
+	}
+	playerID, gameID := client.playerID, client.gameID
+	client.mu.Unlock()
 
-		// Mark player as disconnected
-		if client.playerID != "" {
-			s.gameManager.SetPlayerDisconnected(client.playerID)
-		}
+	// Mark player as disconnected and let the opponent know
+	if playerID != "" {
+		s.gameManager.SetPlayerDisconnected(playerID)
+		s.notifyOpponentDisconnected(gameID, playerID)
 	}
 }
 
+// notifyOpponentDisconnected tells the rest of the game that a player dropped
+// and how long they have to come back before forfeiting
+func (s *Server) notifyOpponentDisconnected(gameID, playerID string) {
+	gameObj, err := s.gameManager.GetGame(gameID)
+	if err != nil {
+		return
+	}
+
+	snap := gameObj.Snapshot()
+	player := snap.GetPlayer(playerID)
+	if snap.Status != game.StatusInProgress || player == nil || player.DisconnectedAt == nil {
+		return
+	}
+
+	window := s.gameManager.ReconnectWindow()
+	s.publishGameEvent(gameID, protocol.TypeOpponentDisconnected, protocol.OpponentDisconnected{
+		PlayerID:            player.ID,
+		Username:            player.Username,
+		ForfeitAt:           player.DisconnectedAt.Add(window),
+		SecondsUntilForfeit: int(window.Seconds()),
+	})
+}
+
 func (client *WSClient) readPump() {
 	defer func() {
 		client.server.unregisterClient(client)
@@ -90,12 +152,12 @@ func (client *WSClient) readPump() {
 	})
 
 	for {
-		_, message, err := client.conn.ReadMessage()
+		frameType, message, err := client.conn.ReadMessage()
 		if err != nil {
 			break
 		}
 
-		client.handleMessage(message)
+		client.handleMessage(frameType, message)
 	}
 }
 
@@ -111,24 +173,15 @@ func (client *WSClient) writePump() {
 		case message, ok := <-client.send:
 			client.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if !ok {
-				client.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				client.mu.Lock()
+				closeMsg := client.closeMsg
+				client.mu.Unlock()
+				client.conn.WriteMessage(websocket.CloseMessage, closeMsg)
 				return
 			}
 
-			w, err := client.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
-			// Add queued messages
-			n := len(client.send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
-				w.Write(<-client.send)
-			}
-
-			if err := w.Close(); err != nil {
+			// One message per frame, so clients never have to split frames
+			if err := client.conn.WriteMessage(client.frameType(), message); err != nil {
 				return
 			}
 
@@ -141,54 +194,95 @@ func (client *WSClient) writePump() {
 	}
 }
 
-func (client *WSClient) handleMessage(message []byte) {
-	var wsMsg WSMessage
-	if err := json.Unmarshal(message, &wsMsg); err != nil {
-		client.sendError("Invalid message format")
+// handleMessage decodes a message according to its frame: binary frames
+// carry protobuf, text frames JSON
+func (client *WSClient) handleMessage(frameType int, message []byte) {
+	decode := protocol.DecodeClient
+	if frameType == websocket.BinaryMessage {
+		decode = protocol.DecodeBinaryClient
+	}
+
+	msgType, payload, err := decode(message)
+	if err != nil {
+		client.sendError(err)
 		return
 	}
 
-	switch wsMsg.Type {
-	case "join":
-		client.handleJoin(wsMsg.Payload)
-	case "move":
-		client.handleMove(wsMsg.Payload)
-	case "reconnect":
-		client.handleReconnect(wsMsg.Payload)
-	case "heartbeat":
+	first := !client.handshake
+	client.handshake = true
+
+	switch data := payload.(type) {
+	case *protocol.Hello:
+		client.handleHello(data, first)
+	case *protocol.Join:
+		client.handleJoin(data)
+	case *protocol.Move:
+		client.handleMove(data)
+	case *protocol.Reconnect:
+		client.handleReconnect(data)
+	case *protocol.Heartbeat:
 		client.handleHeartbeat()
+	case *protocol.Ack:
+		client.handleAck(data)
+	case *protocol.Resync:
+		client.handleResync(data)
+	case *protocol.CancelSearch:
+		client.handleCancelSearch()
 	default:
-		client.sendError("Unknown message type")
+		client.sendError(fmt.Errorf("%w %q", protocol.ErrUnknownMessageType, msgType))
 	}
 }
 
-func (client *WSClient) handleJoin(payload json.RawMessage) {
-	var data struct {
-		Username string `json:"username"`
-	}
-
-	if err := json.Unmarshal(payload, &data); err != nil {
-		client.sendError("Invalid join payload")
+// handleHello negotiates the protocol version. It must be the first message
+// on the connection; clients that never send it speak protocol.Version.
+func (client *WSClient) handleHello(data *protocol.Hello, first bool) {
+	if !first {
+		client.sendError(fmt.Errorf("%w: hello must be the first message", protocol.ErrInvalidMessage))
 		return
 	}
 
-	if data.Username == "" {
-		client.sendError("Username is required")
+	version, ok := protocol.Negotiate(data.Versions)
+	if !ok {
+		client.sendError(fmt.Errorf("%w: none of %v, server supports %v", protocol.ErrUnsupportedVersion, data.Versions, protocol.SupportedVersions))
+		client.closeWith(websocket.FormatCloseMessage(websocket.CloseProtocolError, "unsupported protocol version"))
 		return
 	}
 
+	client.version = version
+	client.sendMessage(protocol.TypeWelcome, protocol.Welcome{
+		Version:           version,
+		SupportedVersions: protocol.SupportedVersions,
+	})
+}
+
+func (client *WSClient) handleJoin(data *protocol.Join) {
 	// Add player to matchmaking. matchmaker now returns a matched flag to
 	// indicate whether a second player was found immediately. We defer
-	// calling JoinGame until after we set the WS client fields so the
+	// calling JoinMatch until after we set the WS client fields so the
 	// game update callback can find both clients.
-	player, gameObj, matched := client.server.matchmaker.AddPlayer(data.Username)
+	player, err := client.server.newPlayer(context.Background(), client.apiKey, data)
+	if err != nil {
+		client.sendError(err)
+		return
+	}
+
+	gameObj, matched, err := client.server.matchmaker.AddPlayer(player, data.Queue)
+	if errors.Is(err, game.ErrAlreadyPlaying) {
+		if token, ok := client.server.activeSession(client.apiKey, data, player.Username); ok {
+			client.resumeJoin(token)
+			return
+		}
+	}
+	if err != nil {
+		client.sendError(err)
+		return
+	}
 
 	// Assign client identifiers immediately so the client is discoverable
 	// by server-level broadcasts.
-	client.playerID = player.ID
-	client.gameID = gameObj.ID
+	client.setIDs(player.ID, gameObj.ID)
 
-	log.Printf("Client joined: player_id=%s game_id=%s remote=%s", client.playerID, client.gameID, client.conn.RemoteAddr().String())
+	log.Printf("Client joined: player_id=%s game_id=%s remote=%s", player.ID, gameObj.ID, client.conn.RemoteAddr().String())
 
 	// If a match was found, explicitly join the game now (this will emit
 	// events and trigger the onGameUpdate callback which will broadcast
@@ -196,188 +290,322 @@ func (client *WSClient) handleJoin(payload json.RawMessage) {
 	// client.gameID avoids the race where the callback fires before the
 	// WS client is ready to receive it.
 	if matched {
-		if err := client.server.gameManager.JoinGame(gameObj.ID, player); err != nil {
+		if err := client.server.matchmaker.JoinMatch(gameObj, player); err != nil {
 			log.Printf("Error joining matched game: %v", err)
-			client.sendError("Failed to join matched game")
+			client.sendError(game.ErrMatchFailed)
 			return
 		}
 	}
 
 	// Send player info including session token for reconnect
-	client.sendMessage("player_info", map[string]interface{}{
-		"player_id":     player.ID,
-		"game_id":       gameObj.ID,
-		"username":      player.Username,
-		"session_token": player.SessionToken,
+	client.sendMessage(protocol.TypePlayerInfo, protocol.PlayerInfo{
+		PlayerID:     player.ID,
+		GameID:       gameObj.ID,
+		Username:     player.Username,
+		SessionToken: player.SessionToken,
 	})
 
-	// Always broadcast current game state to ensure all connected clients get the update
-	// This is critical when player 2 joins - both clients need to transition from waiting to playing
-	client.broadcastGameState(gameObj)
+	// Send the current state to this client; once the game starts, further
+	// updates reach every client in the game through the manager's callback
+	client.server.attachToGame(client, player.ID, gameObj, nil)
 
 	// If game is waiting for player 2, also send waiting message to this client
-	if gameObj.Player2 == nil {
-		client.sendMessage("waiting", map[string]interface{}{
-			"message": "Waiting for opponent...",
+	if !matched {
+		client.sendMessage(protocol.TypeWaiting, protocol.Waiting{
+			Message: "Waiting for opponent...",
 		})
-	}
-
-	// If playing against bot and bot's turn, make bot move
-	if gameObj.Player2 != nil && gameObj.Player2.IsBot && gameObj.CurrentTurn == game.Player2 {
-		go func() {
-			time.Sleep(1 * time.Second)
-			client.server.gameManager.HandleBotMove(gameObj.ID)
-			if g, err := client.server.gameManager.GetGame(gameObj.ID); err == nil {
-				client.broadcastGameState(g)
-			}
-		}()
+		if status, ok := client.server.matchmaker.QueueStatus(player.ID); ok {
+			client.sendMessage(protocol.TypeQueueStatus, protocol.NewQueueStatus(status))
+		}
 	}
 }
 
-func (client *WSClient) handleMove(payload json.RawMessage) {
-	var data struct {
-		Column int `json:"column"`
+// resumeJoin answers a join from an account that already has a matchmaking
+// game by resuming it, as a reconnect would; other connections of the
+// player are replaced. A player still waiting is told their queue status.
+func (client *WSClient) resumeJoin(sessionToken string) {
+	if err := client.server.resumeClient(client, sessionToken, nil); err != nil {
+		client.sendError(err)
+		return
 	}
 
-	if err := json.Unmarshal(payload, &data); err != nil {
-		client.sendError("Invalid move payload")
-		return
+	playerID, _ := client.ids()
+	if status, ok := client.server.matchmaker.QueueStatus(playerID); ok {
+		client.sendMessage(protocol.TypeWaiting, protocol.Waiting{
+			Message: "Waiting for opponent...",
+		})
+		client.sendMessage(protocol.TypeQueueStatus, protocol.NewQueueStatus(status))
 	}
+}
 
-	if client.gameID == "" || client.playerID == "" {
-		client.sendError("Not in a game")
-		return
+// sendQueueStatus tells a waiting player's clients where they stand in
+// their queue
+func (s *Server) sendQueueStatus(status game.QueueStatus) {
+	payload := protocol.NewQueueStatus(status)
+	for _, client := range s.playerClients(status.PlayerID) {
+		client.sendMessage(protocol.TypeQueueStatus, payload)
 	}
+}
 
-	// Make the move
-	_, err := client.server.gameManager.MakeMove(client.gameID, client.playerID, data.Column)
-	if err != nil {
-		client.sendError(err.Error())
-		return
+// handleCancelSearch leaves matchmaking; the connection stays open so the
+// client can join again
+func (client *WSClient) handleCancelSearch() {
+	playerID, _ := client.ids()
+	if err := client.server.cancelSearch(playerID); err != nil {
+		client.sendError(err)
 	}
+}
 
-	// Get updated game state
-	gameObj, err := client.server.gameManager.GetGame(client.gameID)
-	if err != nil {
-		client.sendError("Game not found")
-		return
+// cancelSearch takes a waiting player out of matchmaking, discarding the
+// game created for them, and unbinds their clients after telling them. SSE
+// streams are closed, as their session no longer exists.
+func (s *Server) cancelSearch(playerID string) error {
+	if playerID == "" || !s.matchmaker.RemovePlayer(playerID) {
+		return game.ErrNotSearching
 	}
 
-	// Broadcast updated state
-	client.broadcastGameState(gameObj)
+	s.endSearch(playerID, "Search cancelled")
+	return nil
+}
 
-	// If bot's turn, make bot move
-	if gameObj.Status == game.StatusInProgress &&
-		gameObj.Player2 != nil &&
-		gameObj.Player2.IsBot &&
-		gameObj.CurrentTurn == game.Player2 {
-		go func() {
-			time.Sleep(500 * time.Millisecond)
-			if err := client.server.gameManager.HandleBotMove(client.gameID); err != nil {
-				return
-			}
-			if g, err := client.server.gameManager.GetGame(client.gameID); err == nil {
-				client.broadcastGameState(g)
-			}
-		}()
+// endSearch unbinds the clients of a player taken out of matchmaking and
+// tells them why
+func (s *Server) endSearch(playerID, message string) {
+	for _, client := range s.playerClients(playerID) {
+		client.setIDs("", "")
+		client.sendMessage(protocol.TypeSearchCancelled, protocol.SearchCancelled{
+			Message: message,
+		})
+		if client.sse {
+			client.closeWith(nil)
+		}
 	}
 }
 
-func (client *WSClient) handleReconnect(payload json.RawMessage) {
-	var data struct {
-		SessionToken string `json:"session_token"`
+// followMatch moves the clients of a waiting player into the game the
+// matchmaker paired them into, as if they had just joined it
+func (s *Server) followMatch(player *game.Player, gameObj *game.Game) {
+	for _, client := range s.playerClients(player.ID) {
+		client.sendMessage(protocol.TypePlayerInfo, protocol.PlayerInfo{
+			PlayerID:     player.ID,
+			GameID:       gameObj.ID,
+			Username:     player.Username,
+			SessionToken: player.SessionToken,
+		})
+		s.attachToGame(client, player.ID, gameObj, nil)
 	}
+}
 
-	if err := json.Unmarshal(payload, &data); err != nil {
-		client.sendError("Invalid reconnect payload")
+func (client *WSClient) handleMove(data *protocol.Move) {
+	playerID, gameID := client.ids()
+	if gameID == "" || playerID == "" {
+		client.sendError(game.ErrNotInGame)
 		return
 	}
 
-	if data.SessionToken == "" {
-		client.sendError("Session token is required")
+	// Make the move; the resulting state (and any bot reply) is broadcast
+	// by the game manager
+	if _, err := client.server.gameManager.MakeMove(gameID, playerID, *data.Column); err != nil {
+		client.sendError(err)
 		return
 	}
+}
+
+// handleReconnect resumes a game. When the client says which event it saw
+// last, the events it missed are replayed instead of sending the full state.
+func (client *WSClient) handleReconnect(data *protocol.Reconnect) {
+	if err := client.server.resumeClient(client, data.SessionToken, data.LastSeq); err != nil {
+		client.sendError(fmt.Errorf("Reconnect failed: %w", err))
+	}
+}
 
-	// Try to reconnect using session token
-	gameObj, player, err := client.server.gameManager.ReconnectPlayer(data.SessionToken)
+// resumeClient binds a client to the player owning sessionToken and catches
+// it up from lastSeq. WebSocket reconnects and SSE streams both start here.
+func (s *Server) resumeClient(client *WSClient, sessionToken string, lastSeq *uint64) error {
+	wasAway := true
+	if _, before, err := s.gameManager.GetGameBySession(sessionToken); err == nil {
+		wasAway = !before.Connected
+	}
+
+	gameObj, player, err := s.gameManager.ReconnectPlayer(sessionToken)
 	if err != nil {
-		log.Printf("Reconnect failed for session %s: %v", data.SessionToken, err)
-		client.sendError(fmt.Sprintf("Reconnect failed: %v", err))
-		return
+		log.Printf("Reconnect failed for session %s: %v", sessionToken, err)
+		return err
 	}
 
-	// Set client identifiers
-	client.playerID = player.ID
-	client.gameID = gameObj.ID
+	// A previous socket for this player may still be open (e.g. a stale tab
+	// or a half-dead connection); make sure only this one gets updates
+	s.evictPlayerClients(player.ID, client)
 
-	log.Printf("Client reconnected: player=%s game=%s session=%s", player.Username, gameObj.ID, data.SessionToken)
+	log.Printf("Client reconnected: player=%s game=%s session=%s", player.Username, gameObj.ID, sessionToken)
 
 	// Send reconnection success with full player info
-	client.sendMessage("reconnected", map[string]interface{}{
-		"game_id":       gameObj.ID,
-		"player_id":     player.ID,
-		"username":      player.Username,
-		"session_token": player.SessionToken,
+	client.sendMessage(protocol.TypeReconnected, protocol.Reconnected{
+		GameID:       gameObj.ID,
+		PlayerID:     player.ID,
+		Username:     player.Username,
+		SessionToken: player.SessionToken,
 	})
 
-	// Send current game state
-	client.broadcastGameState(gameObj)
+	// Catch the client up, then tell the opponent the player is back. A
+	// client that does not say where it stopped resumes from its last ack,
+	// except protobuf clients: their deltas need the exact state they hold.
+	if lastSeq == nil && !client.binary {
+		lastSeq = s.findGameEventLog(gameObj.ID).ackedSeq(player.ID)
+	}
+	s.attachToGame(client, player.ID, gameObj, lastSeq)
+
+	if wasAway && gameObj.Snapshot().Status == game.StatusInProgress {
+		s.publishGameEvent(gameObj.ID, protocol.TypeOpponentReconnected, protocol.OpponentReconnected{
+			PlayerID: player.ID,
+			Username: player.Username,
+		})
+	}
+	return nil
 }
 
-func (client *WSClient) handleHeartbeat() {
-	if client.playerID != "" {
-		client.server.gameManager.UpdatePlayerHeartbeat(client.playerID)
-		// Also push current game state to help clients transition from 'waiting' to active
-		if client.gameID != "" {
-			if g, err := client.server.gameManager.GetGame(client.gameID); err == nil {
-				// If a bot or second player joined since last update, this ensures the client receives it
-				client.broadcastGameState(g)
-			}
+// evictPlayerClients detaches every client bound to playerID other than keep
+// and closes it once it has been told why. The evicted clients no longer
+// count as the player, so closing them does not mark the player as
+// disconnected.
+func (s *Server) evictPlayerClients(playerID string, keep *WSClient) {
+	for _, c := range s.playerClients(playerID) {
+		if c == keep {
+			continue
 		}
+		c.setIDs("", "")
+		c.sendMessage(protocol.TypeSessionReplaced, protocol.SessionReplaced{
+			Message: "This game was resumed from another connection",
+		})
+		c.closeWith(websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session replaced"))
+		log.Printf("Evicted stale client for player %s", playerID)
 	}
 }
 
-func (client *WSClient) sendMessage(msgType string, payload interface{}) {
-	msg := map[string]interface{}{
-		"type":    msgType,
-		"payload": payload,
+func (client *WSClient) handleHeartbeat() {
+	playerID, gameID := client.ids()
+	if playerID != "" {
+		client.server.gameManager.UpdatePlayerHeartbeat(playerID)
+		// Reply with the latest sequence number; a client that is behind
+		// it asks for a resync instead of us re-sending the whole state
+		client.sendMessage(protocol.TypeHeartbeatAck, protocol.HeartbeatAck{
+			Seq: client.server.findGameEventLog(gameID).lastSeq(),
+		})
 	}
+}
+
+// handleAck records the last event the client has processed. Reconnects
+// that do not carry a last_seq resume from here.
+func (client *WSClient) handleAck(data *protocol.Ack) {
+	playerID, gameID := client.ids()
+	if gameID == "" {
+		return
+	}
+	client.server.findGameEventLog(gameID).ack(playerID, data.Seq)
+}
 
-	data, err := json.Marshal(msg)
+// handleResync replays the events after from_seq, or sends the full state
+// if they are no longer available
+func (client *WSClient) handleResync(data *protocol.Resync) {
+	_, gameID := client.ids()
+	gameObj, err := client.server.gameManager.GetGame(gameID)
 	if err != nil {
+		client.sendError(game.ErrNotInGame)
 		return
 	}
 
-	select {
-	case client.send <- data:
-	default:
-		// Client buffer full, disconnect
-		client.server.unregisterClient(client)
+	client.server.resync(client, gameObj, data.FromSeq)
+}
+
+// sendMessage sends a message that is not part of a game's event sequence.
+// payload must be the type registered for msgType in protocol.ServerMessages.
+func (client *WSClient) sendMessage(msgType string, payload interface{}) {
+	client.sendEnvelope(protocol.ServerEnvelope{Type: msgType, Payload: payload})
+}
+
+// sendEvent sends a sequenced game event
+func (client *WSClient) sendEvent(ev gameEvent) {
+	client.sendEnvelope(protocol.ServerEnvelope{Type: ev.Type, Seq: ev.Seq, Payload: ev.Payload, Delta: ev.Delta})
+}
+
+// frameType returns the WebSocket frame type of the client's subprotocol
+func (client *WSClient) frameType() int {
+	if client.binary {
+		return websocket.BinaryMessage
 	}
+	return websocket.TextMessage
 }
 
-func (client *WSClient) sendError(message string) {
-	client.sendMessage("error", map[string]interface{}{
-		"message": message,
-	})
+// encode serializes msg in the client's subprotocol
+func (client *WSClient) encode(msg protocol.ServerEnvelope) ([]byte, error) {
+	switch {
+	case client.binary:
+		return protocol.EncodeBinary(msg)
+	case client.sse:
+		return encodeSSE(msg)
+	}
+	return json.Marshal(msg)
 }
 
-func (client *WSClient) broadcastGameState(gameObj *game.Game) {
-	gameData, err := gameObj.ToJSON()
+func (client *WSClient) sendEnvelope(msg protocol.ServerEnvelope) {
+	data, err := client.encode(msg)
 	if err != nil {
+		log.Printf("Failed to encode %s message: %v", msg.Type, err)
+		return
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.closed {
 		return
 	}
 
-	var gameMap map[string]interface{}
-	json.Unmarshal(gameData, &gameMap)
+	select {
+	case client.send <- data:
+	default:
+		// The client cannot keep up. Rather than silently dropping events,
+		// close the socket with a code asking it to reconnect and resume
+		// from its last sequence number.
+		log.Printf("Send buffer full, closing slow client")
+		client.closeLocked(websocket.FormatCloseMessage(closeCodeResync, "too far behind, resume from last seq"))
+	}
+}
 
-	// Broadcast to all clients in this game
-	client.server.mu.RLock()
-	defer client.server.mu.RUnlock()
+// closeWith closes the connection with closeMsg once queued messages are sent
+func (client *WSClient) closeWith(closeMsg []byte) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if !client.closed {
+		client.closeLocked(closeMsg)
+	}
+}
 
-	for c := range client.server.clients {
-		if c.gameID == gameObj.ID {
-			c.sendMessage("game_update", gameMap)
-		}
+// closeLocked stops accepting messages; writePump sends what is queued,
+// then closeMsg. The caller must hold client.mu and check client.closed.
+func (client *WSClient) closeLocked(closeMsg []byte) {
+	client.closeMsg = closeMsg
+	client.closed = true
+	close(client.send)
+}
+
+// sendError reports a failed request with the code from protocol's error
+// catalogue
+func (client *WSClient) sendError(err error) {
+	payload := protocol.NewError(err)
+	if payload.Code == protocol.CodeInternal {
+		log.Printf("Internal error: %v", err)
 	}
+	client.sendMessage(protocol.TypeError, payload)
+}
+
+// sendGameState sends a game_update with the given state to this client
+// only. seq is the game's latest event sequence number, from which the
+// client can later resume.
+func (client *WSClient) sendGameState(snap *game.Snapshot, seq uint64) {
+	client.sendEnvelope(protocol.ServerEnvelope{
+		Type:    protocol.TypeGameUpdate,
+		Seq:     seq,
+		Payload: protocol.NewGameState(snap),
+	})
 }
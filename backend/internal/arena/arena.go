@@ -0,0 +1,496 @@
+// Package arena runs arenas: events that last a fixed window, during which
+// players play as many games as they can. Each arena has a matchmaking
+// queue of its own, open from its start to its end. Players join it like
+// any other queue, and whoever finishes a game in it while the arena runs
+// is put straight back into it to be paired with someone else there.
+// Every game scores points, double for players on a winning streak, and
+// the players in the arena are sent its leaderboard after every game.
+package arena
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/4-in-a-row/internal/clock"
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+// Arena statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusRunning   = "running"
+	StatusFinished  = "finished"
+)
+
+// QueuePrefix starts the name of every arena's matchmaking queue, which
+// goes on with the arena's ID
+const QueuePrefix = "arena-"
+
+// requeueAttempts bounds how often a finished player is queued again after
+// the game they were paired into could not be joined
+const requeueAttempts = 3
+
+// Arena is an arena with its leaderboard
+type Arena struct {
+	database.Arena
+	Queue        string     `json:"queue"`         // the matchmaking queue to join to play in it
+	RemainingSec int        `json:"remaining_sec"` // until it ends, 0 once it has
+	Standings    []Standing `json:"standings"`
+}
+
+// arena is the state of an arena the director follows: those scheduled or
+// running, and those that ended with games still being played
+type arena struct {
+	database.Arena
+	players   map[string]*database.ArenaPlayer // by username
+	playerIDs map[string]string                // username -> the player they are in the arena as now
+	games     int                              // games in progress
+}
+
+// Director schedules arenas, opening and closing their queues on time, and
+// scores their games
+type Director struct {
+	manager    *game.Manager
+	matchmaker *game.Matchmaker
+	db         *database.DB
+	clock      clock.Clock
+
+	mu            sync.Mutex
+	arenas        map[string]*arena
+	games         map[string]string // gameID -> arenaID, for games in progress
+	onLeaderboard func(a *Arena, playerIDs []string)
+	onRequeue     func(previousID string, player *game.Player, g *game.Game)
+	onClosed      func(waiting []*game.Player)
+}
+
+// NewDirector creates a director that pairs arena games through matchmaker
+// and follows them through manager
+func NewDirector(manager *game.Manager, matchmaker *game.Matchmaker, db *database.DB, clk clock.Clock) *Director {
+	d := &Director{
+		manager:    manager,
+		matchmaker: matchmaker,
+		db:         db,
+		clock:      clk,
+		arenas:     make(map[string]*arena),
+		games:      make(map[string]string),
+	}
+	manager.AddGameStartedCallback(d.handleGameStarted)
+	manager.AddGameFinishedCallback(d.handleGameFinished)
+	return d
+}
+
+// SetLeaderboardCallback sets a callback that receives an arena's
+// leaderboard whenever it changes, with the players in the arena to send it
+// to
+func (d *Director) SetLeaderboardCallback(callback func(a *Arena, playerIDs []string)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onLeaderboard = callback
+}
+
+// SetRequeueCallback sets a callback for when a player who finished an
+// arena game is queued again, as a new player, so that the clients of the
+// previous one can follow. g is the game created for them, or the one they
+// were paired into straight away and have joined. Both are nil if the
+// player could not be queued again.
+func (d *Director) SetRequeueCallback(callback func(previousID string, player *game.Player, g *game.Game)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onRequeue = callback
+}
+
+// SetClosedCallback sets a callback for the players still waiting in an
+// arena's queue when the arena ends, whose games have been discarded
+func (d *Director) SetClosedCallback(callback func(waiting []*game.Player)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onClosed = callback
+}
+
+// Load picks up the arenas that were scheduled or running when the server
+// last stopped. Running arenas get their queue back; their games did not
+// survive the restart, so their players have to join again.
+func (d *Director) Load(ctx context.Context) error {
+	arenas, err := d.db.ListArenas(ctx, 0, StatusScheduled, StatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to list arenas: %w", err)
+	}
+
+	for _, summary := range arenas {
+		dbA, players, err := d.db.GetArena(ctx, summary.ID)
+		if err != nil {
+			return err
+		}
+		a := newArena(*dbA, players)
+		if a.Status == StatusRunning {
+			if err := d.matchmaker.AddQueue(a.queueRules()); err != nil {
+				return err
+			}
+			log.Printf("Resumed arena %s", a.ID)
+		}
+
+		d.mu.Lock()
+		d.arenas[a.ID] = a
+		d.mu.Unlock()
+	}
+	return nil
+}
+
+// Run starts and ends arenas on time; it returns when ctx is cancelled
+func (d *Director) Run(ctx context.Context) {
+	ticker := d.clock.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			d.tick(ctx)
+		}
+	}
+}
+
+// Create schedules an arena from startsAt, or straight away if that has
+// passed, for duration. A zero turnTimeout means the server's.
+func (d *Director) Create(ctx context.Context, name string, startsAt time.Time, duration, turnTimeout time.Duration, rated bool) (*Arena, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	if now := d.clock.Now(); startsAt.Before(now) {
+		startsAt = now
+	}
+	startsAt = startsAt.UTC().Truncate(time.Second)
+
+	a := newArena(database.Arena{
+		ID:             id,
+		Name:           name,
+		Status:         StatusScheduled,
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.Add(duration),
+		TurnTimeoutSec: int(turnTimeout / time.Second),
+		Rated:          rated,
+	}, nil)
+	if err := d.db.CreateArena(ctx, &a.Arena); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.arenas[a.ID] = a
+	return a.view(d.clock.Now()), nil
+}
+
+// Get returns an arena with its leaderboard
+func (d *Director) Get(ctx context.Context, id string) (*Arena, error) {
+	d.mu.Lock()
+	if a, ok := d.arenas[id]; ok {
+		defer d.mu.Unlock()
+		return a.view(d.clock.Now()), nil
+	}
+	d.mu.Unlock()
+
+	dbA, players, err := d.db.GetArena(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newArena(*dbA, players).view(d.clock.Now()), nil
+}
+
+// List returns arenas, the latest to start first, only those with status
+// unless it is empty
+func (d *Director) List(ctx context.Context, limit int, status string) ([]database.Arena, error) {
+	if status == "" {
+		return d.db.ListArenas(ctx, limit)
+	}
+	return d.db.ListArenas(ctx, limit, status)
+}
+
+// tick starts the arenas that are due and ends those whose time is up
+func (d *Director) tick(ctx context.Context) {
+	now := d.clock.Now()
+
+	d.mu.Lock()
+	var starting, ending []*arena
+	for id, a := range d.arenas {
+		switch {
+		case a.Status == StatusScheduled && !now.Before(a.StartsAt):
+			a.Status = StatusRunning
+			starting = append(starting, a)
+		case a.Status == StatusRunning && !now.Before(a.EndsAt):
+			a.Status = StatusFinished
+			ending = append(ending, a)
+			if a.games == 0 {
+				delete(d.arenas, id)
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	// The matchmaker calls back into the director when it pairs players,
+	// so queues are opened and closed without d.mu held
+	for _, a := range starting {
+		if err := d.matchmaker.AddQueue(a.queueRules()); err != nil {
+			log.Printf("Failed to open arena %s: %v", a.ID, err)
+		}
+		if err := d.db.SetArenaStatus(ctx, a.ID, StatusRunning); err != nil {
+			log.Printf("Failed to save arena %s: %v", a.ID, err)
+		}
+		log.Printf("Arena %s started, ends at %s", a.ID, a.EndsAt.Format(time.RFC3339))
+	}
+
+	for _, a := range ending {
+		waiting := d.matchmaker.RemoveQueue(a.queueRules().Name)
+		if err := d.db.SetArenaStatus(ctx, a.ID, StatusFinished); err != nil {
+			log.Printf("Failed to save arena %s: %v", a.ID, err)
+		}
+
+		d.mu.Lock()
+		onClosed := d.onClosed
+		view, playerIDs := a.view(now), a.recipients()
+		d.mu.Unlock()
+
+		if onClosed != nil && len(waiting) > 0 {
+			onClosed(waiting)
+		}
+		d.publish(view, playerIDs)
+		log.Printf("Arena %s finished with %d players", a.ID, len(view.Standings))
+	}
+}
+
+// handleGameStarted follows a game paired in an arena's queue. Players new
+// to the arena are sent its leaderboard, which now lists them.
+func (d *Director) handleGameStarted(snap *game.Snapshot) {
+	d.mu.Lock()
+	a := d.arenaOf(snap)
+	if a == nil || a.Status != StatusRunning {
+		d.mu.Unlock()
+		return
+	}
+	d.games[snap.ID] = a.ID
+	a.games++
+
+	var newcomers []string
+	for _, p := range []*game.Player{snap.Player1, snap.Player2} {
+		if p == nil || p.Hosted {
+			continue
+		}
+		if _, ok := a.players[p.Username]; !ok {
+			newcomers = append(newcomers, p.ID)
+		}
+		a.player(p)
+		a.playerIDs[p.Username] = p.ID
+	}
+	view := a.view(d.clock.Now())
+	d.mu.Unlock()
+
+	if len(newcomers) > 0 {
+		d.publish(view, newcomers)
+	}
+}
+
+// handleGameFinished scores an arena game and, while the arena runs, puts
+// its players back in the queue. Players who left are not queued again and
+// stop receiving the leaderboard until they join again.
+func (d *Director) handleGameFinished(snap *game.Snapshot) {
+	d.mu.Lock()
+	id, ok := d.games[snap.ID]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.games, snap.ID)
+	a := d.arenas[id]
+	a.games--
+	if a.Status == StatusFinished && a.games == 0 {
+		delete(d.arenas, id)
+	}
+
+	now := d.clock.Now()
+	running := a.Status == StatusRunning && now.Before(a.EndsAt)
+	var scored []database.ArenaPlayer
+	var requeue []*game.Player
+	for _, p := range []*game.Player{snap.Player1, snap.Player2} {
+		if p == nil || p.Hosted {
+			continue
+		}
+		player := a.player(p)
+		record(player, snap)
+		scored = append(scored, *player)
+
+		switch {
+		case running && p.Connected:
+			requeue = append(requeue, p)
+		case !p.Connected:
+			delete(a.playerIDs, p.Username)
+		}
+	}
+	view, playerIDs := a.view(now), a.recipients()
+	queue := a.queueRules().Name
+	d.mu.Unlock()
+
+	if err := d.db.SaveArenaPlayers(context.Background(), id, scored...); err != nil {
+		log.Printf("Failed to save arena %s scores: %v", id, err)
+	}
+	// Sent before requeueing, while the players' clients are still bound
+	// to the players who just finished
+	d.publish(view, playerIDs)
+
+	for _, p := range requeue {
+		d.requeue(id, queue, p)
+	}
+}
+
+// requeue puts a player who finished an arena game back in its queue as a
+// new player with the same account, joining them to their next game if an
+// opponent was already waiting. The requeue callback hears of it either
+// way, so the player's clients never stay on the finished game.
+func (d *Director) requeue(arenaID, queue string, previous *game.Player) {
+	player := d.manager.NewPlayer(previous.Username)
+	if previous.IsBot {
+		player = d.manager.NewBotPlayer(previous.Username)
+	}
+	player.Rating = previous.Rating
+
+	g, err := d.enqueue(player, queue)
+	if err != nil {
+		log.Printf("Failed to queue %s again in arena %s: %v", player.Username, arenaID, err)
+		player, g = nil, nil
+	}
+
+	d.mu.Lock()
+	if a, ok := d.arenas[arenaID]; ok && player != nil {
+		a.playerIDs[player.Username] = player.ID
+	}
+	onRequeue := d.onRequeue
+	d.mu.Unlock()
+
+	if onRequeue != nil {
+		onRequeue(previous.ID, player, g)
+	}
+}
+
+// enqueue adds player to queue and joins them to the game they are paired
+// into, if any. When that game cannot be joined the opponent stays queued
+// and the player is queued again, up to requeueAttempts times.
+func (d *Director) enqueue(player *game.Player, queue string) (*game.Game, error) {
+	var err error
+	for range requeueAttempts {
+		var g *game.Game
+		var matched bool
+		g, matched, err = d.matchmaker.AddPlayer(player, queue)
+		if err != nil || !matched {
+			return g, err
+		}
+		if err = d.matchmaker.JoinMatch(g, player); err == nil {
+			return g, nil
+		}
+		log.Printf("Failed to join %s to game %s: %v", player.Username, g.ID, err)
+	}
+	return nil, err
+}
+
+// publish hands a leaderboard to the leaderboard callback, if one is set
+func (d *Director) publish(view *Arena, playerIDs []string) {
+	d.mu.Lock()
+	onLeaderboard := d.onLeaderboard
+	d.mu.Unlock()
+
+	if onLeaderboard != nil && len(playerIDs) > 0 {
+		onLeaderboard(view, playerIDs)
+	}
+}
+
+// arenaOf returns the arena whose queue a game was paired in, if the
+// director follows it. d.mu must be held.
+func (d *Director) arenaOf(snap *game.Snapshot) *arena {
+	id, ok := strings.CutPrefix(snap.Queue, QueuePrefix)
+	if !ok {
+		return nil
+	}
+	return d.arenas[id]
+}
+
+func newArena(a database.Arena, players []database.ArenaPlayer) *arena {
+	state := &arena{
+		Arena:     a,
+		players:   make(map[string]*database.ArenaPlayer, len(players)),
+		playerIDs: make(map[string]string),
+	}
+	for i := range players {
+		state.players[players[i].Username] = &players[i]
+	}
+	return state
+}
+
+// player returns the score of a player in the arena, adding them if they
+// are new to it
+func (a *arena) player(p *game.Player) *database.ArenaPlayer {
+	player, ok := a.players[p.Username]
+	if !ok {
+		player = &database.ArenaPlayer{Username: p.Username, IsBot: p.IsBot}
+		a.players[p.Username] = player
+	}
+	return player
+}
+
+// queueRules returns the rules of the arena's queue. Nobody in an arena
+// plays a bot they did not choose, so players wait for a human however
+// long it takes.
+func (a *arena) queueRules() game.QueueRules {
+	return game.QueueRules{
+		Name:        QueuePrefix + a.ID,
+		TurnTimeout: time.Duration(a.TurnTimeoutSec) * time.Second,
+		BotFallback: game.BotFallbackNone,
+		Rated:       a.Rated,
+	}
+}
+
+// view returns the arena with its leaderboard as of now
+func (a *arena) view(now time.Time) *Arena {
+	players := make([]database.ArenaPlayer, 0, len(a.players))
+	for _, p := range a.players {
+		players = append(players, *p)
+	}
+
+	remaining := 0
+	if a.Status != StatusFinished {
+		remaining = int(a.EndsAt.Sub(now).Round(time.Second) / time.Second)
+		remaining = max(remaining, 0)
+	}
+	return &Arena{
+		Arena:        a.Arena,
+		Queue:        a.queueRules().Name,
+		RemainingSec: remaining,
+		Standings:    leaderboard(players),
+	}
+}
+
+// recipients returns the players in the arena to send its leaderboard to
+func (a *arena) recipients() []string {
+	ids := make([]string, 0, len(a.playerIDs))
+	for _, id := range a.playerIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// newID returns a random arena ID, short enough for the queue named after
+// it to fit the queue names stored with games
+func newID() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate arena ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
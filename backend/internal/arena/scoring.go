@@ -0,0 +1,80 @@
+package arena
+
+import (
+	"sort"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+// Points for the result of an arena game. A loss, or a game abandoned
+// without a winner, scores nothing.
+const (
+	PointsWin  = 2
+	PointsDraw = 1
+)
+
+// FireStreak is how many games in a row a player has to win to be on fire:
+// from then on every game scores double until they fail to win one
+const FireStreak = 2
+
+// Standing is a player's place on an arena's leaderboard
+type Standing struct {
+	Rank int `json:"rank"` // shared by players on the same score after the same number of games
+	database.ArenaPlayer
+	OnFire bool `json:"on_fire"` // their next game scores double
+}
+
+// points returns what a result worth base scores for a player who has won
+// streak games in a row before it
+func points(base, streak int) int {
+	if streak >= FireStreak {
+		return 2 * base
+	}
+	return base
+}
+
+// record scores a finished game for one of its players
+func record(p *database.ArenaPlayer, snap *game.Snapshot) {
+	p.Games++
+	switch {
+	case snap.Winner != nil && snap.Winner.Username == p.Username:
+		p.Wins++
+		p.Score += points(PointsWin, p.Streak)
+		p.Streak++
+	case snap.Result == game.ResultDraw:
+		p.Draws++
+		p.Score += points(PointsDraw, p.Streak)
+		p.Streak = 0
+	default:
+		p.Losses++
+		p.Streak = 0
+	}
+}
+
+// leaderboard ranks players by score. Of two players on the same score the
+// one who needed fewer games to get there is ahead.
+func leaderboard(players []database.ArenaPlayer) []Standing {
+	standings := make([]Standing, len(players))
+	for i, p := range players {
+		standings[i] = Standing{ArenaPlayer: p, OnFire: p.Streak >= FireStreak}
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Games != b.Games {
+			return a.Games < b.Games
+		}
+		return a.Username < b.Username
+	})
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Score == standings[i-1].Score && standings[i].Games == standings[i-1].Games {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
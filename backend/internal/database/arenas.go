@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrArenaNotFound is returned when no arena has the requested ID
+var ErrArenaNotFound = errors.New("arena not found")
+
+// Arena is a scheduled arena, see package arena for the meaning of its
+// fields. Times are in UTC.
+type Arena struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	TurnTimeoutSec int       `json:"turn_timeout_sec"` // 0 for the server's turn timeout
+	Rated          bool      `json:"rated"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ArenaPlayer is a player's score in an arena. Streak counts the games they
+// have won in a row.
+type ArenaPlayer struct {
+	Username string `json:"username"`
+	IsBot    bool   `json:"is_bot"`
+	Score    int    `json:"score"`
+	Games    int    `json:"games"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+	Streak   int    `json:"streak"`
+}
+
+const arenaColumns = `id, name, status, starts_at, ends_at, turn_timeout_sec, rated, created_at`
+
+func scanArena(row pgx.Row, a *Arena) error {
+	return row.Scan(
+		&a.ID,
+		&a.Name,
+		&a.Status,
+		&a.StartsAt,
+		&a.EndsAt,
+		&a.TurnTimeoutSec,
+		&a.Rated,
+		&a.CreatedAt,
+	)
+}
+
+// CreateArena saves a new arena and sets its CreatedAt
+func (db *DB) CreateArena(ctx context.Context, a *Arena) error {
+	query := `
+		INSERT INTO arenas (id, name, status, starts_at, ends_at, turn_timeout_sec, rated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return db.pool.QueryRow(ctx, query,
+		a.ID, a.Name, a.Status, a.StartsAt, a.EndsAt, a.TurnTimeoutSec, a.Rated,
+	).Scan(&a.CreatedAt)
+}
+
+// SetArenaStatus records that an arena started or ended
+func (db *DB) SetArenaStatus(ctx context.Context, id, status string) error {
+	_, err := db.pool.Exec(ctx, `UPDATE arenas SET status = $2 WHERE id = $1`, id, status)
+	return err
+}
+
+// SaveArenaPlayers records the scores of players in an arena, in one
+// transaction
+func (db *DB) SaveArenaPlayers(ctx context.Context, arenaID string, players ...ArenaPlayer) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, p := range players {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO arena_players (arena_id, username, is_bot, score, games, wins, draws, losses, streak)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (arena_id, username) DO UPDATE SET
+				score = EXCLUDED.score,
+				games = EXCLUDED.games,
+				wins = EXCLUDED.wins,
+				draws = EXCLUDED.draws,
+				losses = EXCLUDED.losses,
+				streak = EXCLUDED.streak
+		`, arenaID, p.Username, p.IsBot, p.Score, p.Games, p.Wins, p.Draws, p.Losses, p.Streak)
+		if err != nil {
+			return fmt.Errorf("failed to save arena player: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetArena returns an arena with its players, in no particular order
+func (db *DB) GetArena(ctx context.Context, id string) (*Arena, []ArenaPlayer, error) {
+	var a Arena
+	err := scanArena(db.pool.QueryRow(ctx, `SELECT `+arenaColumns+` FROM arenas WHERE id = $1`, id), &a)
+	if err == pgx.ErrNoRows {
+		return nil, nil, ErrArenaNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.pool.Query(ctx, `
+		SELECT username, is_bot, score, games, wins, draws, losses, streak
+		FROM arena_players
+		WHERE arena_id = $1
+	`, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var players []ArenaPlayer
+	for rows.Next() {
+		var p ArenaPlayer
+		if err := rows.Scan(&p.Username, &p.IsBot, &p.Score, &p.Games, &p.Wins, &p.Draws, &p.Losses, &p.Streak); err != nil {
+			return nil, nil, err
+		}
+		players = append(players, p)
+	}
+
+	return &a, players, rows.Err()
+}
+
+// ListArenas returns arenas with one of statuses, or with any status if
+// none are given, the latest to start first. A limit of 0 returns all of
+// them.
+func (db *DB) ListArenas(ctx context.Context, limit int, statuses ...string) ([]Arena, error) {
+	query := `
+		SELECT ` + arenaColumns + `
+		FROM arenas
+		WHERE cardinality($1::text[]) = 0 OR status = ANY($1)
+		ORDER BY starts_at DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := db.pool.Query(ctx, query, statuses, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var arenas []Arena
+	for rows.Next() {
+		var a Arena
+		if err := scanArena(rows, &a); err != nil {
+			return nil, err
+		}
+		arenas = append(arenas, a)
+	}
+
+	return arenas, rows.Err()
+}
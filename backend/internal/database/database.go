@@ -3,11 +3,25 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yourusername/4-in-a-row/internal/rating"
+)
+
+var (
+	// ErrUserNotFound is returned when no user has the requested username
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUsernameTaken is returned when registering a username already in use
+	ErrUsernameTaken = errors.New("username already taken")
+	// ErrNoFinishTime is returned when saving a won or drawn game without
+	// the time it finished, which decides the season it counts towards
+	ErrNoFinishTime = errors.New("decided game has no finish time")
 )
 
 type DB struct {
@@ -17,23 +31,75 @@ type DB struct {
 type GameRecord struct {
 	ID         string      `json:"id"`
 	Player1    string      `json:"player1"`
-	Player2    string      `json:"player2"`
+	Player2    string      `json:"player2"` // "" if nobody joined, stored as NULL
 	Winner     *string     `json:"winner,omitempty"`
 	Result     string      `json:"result"`
 	BoardState [][]int     `json:"board_state"`
+	Queue      string      `json:"queue,omitempty"` // matchmaking queue, "" for private games
+	Rated      bool        `json:"rated"`
 	StartedAt  *time.Time  `json:"started_at,omitempty"`
 	FinishedAt *time.Time  `json:"finished_at,omitempty"`
 	CreatedAt  time.Time   `json:"created_at"`
 }
 
 type User struct {
-	ID        int       `json:"id"`
-	Username  string    `json:"username"`
-	IsBot     bool      `json:"is_bot"`
-	GamesWon  int       `json:"games_won"`
-	GamesLost int       `json:"games_lost"`
-	GamesDrawn int      `json:"games_drawn"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              int        `json:"id"`
+	Username        string     `json:"username"`
+	IsBot           bool       `json:"is_bot"`
+	GamesWon        int        `json:"games_won"`
+	GamesLost       int        `json:"games_lost"`
+	GamesDrawn      int        `json:"games_drawn"`
+	Rating          int        `json:"rating"`
+	RatingDeviation int        `json:"rating_deviation"`
+	Provisional     bool       `json:"provisional"` // too few games for the rating to be reliable
+	WinRate         float64    `json:"win_rate"`    // share of games won, 0 to 1
+	CurrentStreak   int        `json:"current_streak"`
+	LastPlayedAt    *time.Time `json:"last_played_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Rank            int        `json:"rank,omitempty"` // on the leaderboard the user was read from
+}
+
+// RatingChange is one game's effect on a player's rating
+type RatingChange struct {
+	GameID          string    `json:"game_id"`
+	Opponent        string    `json:"opponent"`
+	RatingBefore    int       `json:"rating_before"`
+	RatingAfter     int       `json:"rating_after"`
+	RatingDeviation int       `json:"rating_deviation"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// userColumns are the columns of users that scanUser reads, in order
+const userColumns = `id, username, is_bot, games_won, games_lost, games_drawn, rating, rating_deviation, current_streak, last_played_at, created_at`
+
+// scanUser reads a row selected with userColumns into user, followed by
+// any extra columns into extra
+func scanUser(row pgx.Row, user *User, extra ...any) error {
+	var r, rd float64
+	dest := []any{
+		&user.ID,
+		&user.Username,
+		&user.IsBot,
+		&user.GamesWon,
+		&user.GamesLost,
+		&user.GamesDrawn,
+		&r,
+		&rd,
+		&user.CurrentStreak,
+		&user.LastPlayedAt,
+		&user.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	if games := user.GamesWon + user.GamesLost + user.GamesDrawn; games > 0 {
+		user.WinRate = math.Round(float64(user.GamesWon)/float64(games)*1000) / 1000
+	}
+	user.Rating = int(math.Round(r))
+	user.RatingDeviation = int(math.Round(rd))
+	user.Provisional = rd > rating.ProvisionalDeviation
+	return nil
 }
 
 func NewDB(databaseURL string) (*DB, error) {
@@ -87,6 +153,140 @@ func (db *DB) Migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_games_player2 ON games(player2)`,
 		`CREATE INDEX IF NOT EXISTS idx_games_winner ON games(winner)`,
 		`CREATE INDEX IF NOT EXISTS idx_games_created_at ON games(created_at)`,
+		// Bot accounts: SHA-256 of the API key their clients authenticate with
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS api_key_hash VARCHAR(64) UNIQUE`,
+		// Registered accounts: bcrypt hash of the password. Users without
+		// one are guests, unless they are bots.
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT`,
+		// Glicko-2 ratings, see package rating. Only registered players and
+		// bots are rated.
+		fmt.Sprintf(`ALTER TABLE users ADD COLUMN IF NOT EXISTS rating DOUBLE PRECISION NOT NULL DEFAULT %v`, rating.DefaultRating),
+		fmt.Sprintf(`ALTER TABLE users ADD COLUMN IF NOT EXISTS rating_deviation DOUBLE PRECISION NOT NULL DEFAULT %v`, rating.DefaultDeviation),
+		fmt.Sprintf(`ALTER TABLE users ADD COLUMN IF NOT EXISTS rating_volatility DOUBLE PRECISION NOT NULL DEFAULT %v`, rating.DefaultVolatility),
+		`CREATE INDEX IF NOT EXISTS idx_users_rating ON users(rating)`,
+		`CREATE TABLE IF NOT EXISTS rating_history (
+			id SERIAL PRIMARY KEY,
+			game_id VARCHAR(255) NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+			username VARCHAR(255) NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+			rating_before DOUBLE PRECISION NOT NULL,
+			rating_after DOUBLE PRECISION NOT NULL,
+			deviation_before DOUBLE PRECISION NOT NULL,
+			deviation_after DOUBLE PRECISION NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (game_id, username)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_rating_history_username ON rating_history(username, created_at)`,
+		// Matchmaking queue a game was paired in; casual queues are unrated
+		`ALTER TABLE games ADD COLUMN IF NOT EXISTS queue VARCHAR(32) NOT NULL DEFAULT ''`,
+		`ALTER TABLE games ADD COLUMN IF NOT EXISTS rated BOOLEAN NOT NULL DEFAULT TRUE`,
+		// Tournaments, see package tournament and tournaments.go
+		`CREATE TABLE IF NOT EXISTS tournaments (
+			id VARCHAR(255) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			format VARCHAR(32) NOT NULL,
+			status VARCHAR(32) NOT NULL,
+			rounds INTEGER NOT NULL DEFAULT 0,
+			current_round INTEGER NOT NULL DEFAULT 0,
+			rated BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			started_at TIMESTAMP,
+			finished_at TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_tournaments_status ON tournaments(status, created_at)`,
+		`CREATE TABLE IF NOT EXISTS tournament_players (
+			tournament_id VARCHAR(255) NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+			username VARCHAR(255) NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+			is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+			rating INTEGER NOT NULL,
+			registered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (tournament_id, username)
+		)`,
+		// game_id is not a foreign key: games are only saved once they finish
+		`CREATE TABLE IF NOT EXISTS tournament_pairings (
+			tournament_id VARCHAR(255) NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+			round INTEGER NOT NULL,
+			board INTEGER NOT NULL,
+			player1 VARCHAR(255) NOT NULL,
+			player2 VARCHAR(255),
+			game_id VARCHAR(255),
+			result VARCHAR(32) NOT NULL DEFAULT '',
+			PRIMARY KEY (tournament_id, round, board)
+		)`,
+		// Knockout brackets: the games of each match, see package tournament
+		`ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS best_of INTEGER NOT NULL DEFAULT 1`,
+		`CREATE TABLE IF NOT EXISTS tournament_bracket_games (
+			tournament_id VARCHAR(255) NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+			match_id INTEGER NOT NULL,
+			game_number INTEGER NOT NULL,
+			player1 VARCHAR(255) NOT NULL,
+			player2 VARCHAR(255) NOT NULL,
+			game_id VARCHAR(255),
+			result VARCHAR(32) NOT NULL DEFAULT '',
+			PRIMARY KEY (tournament_id, match_id, game_number)
+		)`,
+		// Arenas, see package arena and arenas.go
+		`CREATE TABLE IF NOT EXISTS arenas (
+			id VARCHAR(32) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			status VARCHAR(32) NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			turn_timeout_sec INTEGER NOT NULL DEFAULT 0,
+			rated BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_arenas_status ON arenas(status, starts_at)`,
+		`CREATE TABLE IF NOT EXISTS arena_players (
+			arena_id VARCHAR(32) NOT NULL REFERENCES arenas(id) ON DELETE CASCADE,
+			username VARCHAR(255) NOT NULL,
+			is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+			score INTEGER NOT NULL DEFAULT 0,
+			games INTEGER NOT NULL DEFAULT 0,
+			wins INTEGER NOT NULL DEFAULT 0,
+			draws INTEGER NOT NULL DEFAULT 0,
+			losses INTEGER NOT NULL DEFAULT 0,
+			streak INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (arena_id, username)
+		)`,
+		// Seasons, see seasons.go: stats and ratings per season, apart from
+		// the lifetime ones in users, and the final standings of past seasons
+		`CREATE TABLE IF NOT EXISTS seasons (
+			name VARCHAR(32) PRIMARY KEY,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			archived_at TIMESTAMP
+		)`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS season_stats (
+			season VARCHAR(32) NOT NULL REFERENCES seasons(name) ON DELETE CASCADE,
+			username VARCHAR(255) NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+			games_won INTEGER NOT NULL DEFAULT 0,
+			games_lost INTEGER NOT NULL DEFAULT 0,
+			games_drawn INTEGER NOT NULL DEFAULT 0,
+			rating DOUBLE PRECISION NOT NULL DEFAULT %v,
+			rating_deviation DOUBLE PRECISION NOT NULL DEFAULT %v,
+			rating_volatility DOUBLE PRECISION NOT NULL DEFAULT %v,
+			PRIMARY KEY (season, username)
+		)`, rating.DefaultRating, rating.DefaultDeviation, rating.DefaultVolatility),
+		`CREATE INDEX IF NOT EXISTS idx_games_finished_at ON games(finished_at)`,
+		`CREATE TABLE IF NOT EXISTS season_standings (
+			season VARCHAR(32) NOT NULL REFERENCES seasons(name) ON DELETE CASCADE,
+			rank INTEGER NOT NULL,
+			username VARCHAR(255) NOT NULL,
+			is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+			games_won INTEGER NOT NULL,
+			games_lost INTEGER NOT NULL,
+			games_drawn INTEGER NOT NULL,
+			rating DOUBLE PRECISION NOT NULL,
+			rating_deviation DOUBLE PRECISION NOT NULL,
+			PRIMARY KEY (season, username)
+		)`,
+		// Leaderboard filters and sorting: the run of wins a user is on, and
+		// when they last finished a game, backfilled from their games
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS current_streak INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS last_played_at TIMESTAMP`,
+		`UPDATE users u SET last_played_at = (
+			SELECT MAX(g.finished_at) FROM games g WHERE g.player1 = u.username OR g.player2 = u.username
+		) WHERE u.last_played_at IS NULL AND u.games_won + u.games_lost + u.games_drawn > 0`,
 	}
 
 	for _, query := range queries {
@@ -109,16 +309,147 @@ func (db *DB) UpsertUser(ctx context.Context, username string, isBot bool) error
 	return err
 }
 
-// SaveGame saves a completed game
+// CreateAccount registers a player account. A username that no account or
+// bot owns yet can be registered even if guests have played under it; its
+// stats start from zero.
+func (db *DB) CreateAccount(ctx context.Context, username, passwordHash string) (*User, error) {
+	query := `
+		INSERT INTO users (username, is_bot, password_hash)
+		VALUES ($1, FALSE, $2)
+		ON CONFLICT (username) DO UPDATE SET
+			password_hash = EXCLUDED.password_hash,
+			games_won = 0,
+			games_lost = 0,
+			games_drawn = 0,
+			rating = DEFAULT,
+			rating_deviation = DEFAULT,
+			rating_volatility = DEFAULT,
+			current_streak = 0,
+			last_played_at = NULL
+		WHERE users.password_hash IS NULL AND users.is_bot = FALSE
+		RETURNING ` + userColumns + `
+	`
+
+	var user User
+	err := scanUser(db.pool.QueryRow(ctx, query, username, passwordHash), &user)
+	if err == pgx.ErrNoRows {
+		return nil, ErrUsernameTaken
+	}
+
+	return &user, err
+}
+
+// GetAccount returns a registered player's account and password hash
+func (db *DB) GetAccount(ctx context.Context, username string) (*User, string, error) {
+	query := `
+		SELECT ` + userColumns + `, password_hash
+		FROM users
+		WHERE username = $1 AND password_hash IS NOT NULL
+	`
+
+	var user User
+	var passwordHash string
+	err := scanUser(db.pool.QueryRow(ctx, query, username), &user, &passwordHash)
+	if err == pgx.ErrNoRows {
+		return nil, "", ErrUserNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &user, passwordHash, nil
+}
+
+// IsUsernameOwned reports whether username belongs to a registered account
+// or a bot, and so cannot be used by guests
+func (db *DB) IsUsernameOwned(ctx context.Context, username string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM users
+			WHERE username = $1 AND (password_hash IS NOT NULL OR is_bot = TRUE)
+		)
+	`
+
+	var owned bool
+	err := db.pool.QueryRow(ctx, query, username).Scan(&owned)
+	return owned, err
+}
+
+// CreateBotAccount registers a bot user that authenticates with the API key
+// hashed as apiKeyHash
+func (db *DB) CreateBotAccount(ctx context.Context, username, apiKeyHash string) (*User, error) {
+	query := `
+		INSERT INTO users (username, is_bot, api_key_hash)
+		VALUES ($1, TRUE, $2)
+		ON CONFLICT (username) DO NOTHING
+		RETURNING ` + userColumns + `
+	`
+
+	var user User
+	err := scanUser(db.pool.QueryRow(ctx, query, username, apiKeyHash), &user)
+	if err == pgx.ErrNoRows {
+		return nil, ErrUsernameTaken
+	}
+
+	return &user, err
+}
+
+// SetBotAPIKey replaces a bot account's API key hash, invalidating its old key
+func (db *DB) SetBotAPIKey(ctx context.Context, username, apiKeyHash string) error {
+	query := `
+		UPDATE users SET api_key_hash = $2
+		WHERE username = $1 AND api_key_hash IS NOT NULL
+	`
+
+	tag, err := db.pool.Exec(ctx, query, username, apiKeyHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// GetBotByAPIKey returns the bot account whose API key hashes to apiKeyHash
+func (db *DB) GetBotByAPIKey(ctx context.Context, apiKeyHash string) (*User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE api_key_hash = $1
+	`
+
+	var user User
+	err := scanUser(db.pool.QueryRow(ctx, query, apiKeyHash), &user)
+	if err == pgx.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+
+	return &user, err
+}
+
+// SaveGame saves a completed game and updates both players' stats and
+// ratings, all in one transaction. A game that was won or drawn must have
+// its FinishedAt set.
 func (db *DB) SaveGame(ctx context.Context, game *GameRecord) error {
+	if _, decided := gameScore(game); decided && game.FinishedAt == nil {
+		return ErrNoFinishTime
+	}
+
 	boardJSON, err := json.Marshal(game.BoardState)
 	if err != nil {
 		return fmt.Errorf("failed to marshal board state: %w", err)
 	}
 
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO games (id, player1, player2, winner, result, board_state, started_at, finished_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO games (id, player1, player2, winner, result, board_state, queue, rated, started_at, finished_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (id) DO UPDATE SET
 			winner = EXCLUDED.winner,
 			result = EXCLUDED.result,
@@ -126,13 +457,15 @@ func (db *DB) SaveGame(ctx context.Context, game *GameRecord) error {
 			finished_at = EXCLUDED.finished_at
 	`
 	
-	_, err = db.pool.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		game.ID,
 		game.Player1,
 		game.Player2,
 		game.Winner,
 		game.Result,
 		boardJSON,
+		game.Queue,
+		game.Rated,
 		game.StartedAt,
 		game.FinishedAt,
 	)
@@ -141,9 +474,14 @@ func (db *DB) SaveGame(ctx context.Context, game *GameRecord) error {
 		return fmt.Errorf("failed to save game: %w", err)
 	}
 
-	// Update user statistics
+	// Update user statistics. Only decided games count, and those have a
+	// finish time.
+	var finished time.Time
+	if game.FinishedAt != nil {
+		finished = *game.FinishedAt
+	}
 	if game.Winner != nil && *game.Winner != "" {
-		if err := db.updateUserStats(ctx, *game.Winner, true, false); err != nil {
+		if err := updateUserStats(ctx, tx, *game.Winner, true, false, finished); err != nil {
 			return err
 		}
 		
@@ -153,51 +491,292 @@ func (db *DB) SaveGame(ctx context.Context, game *GameRecord) error {
 			loser = game.Player2
 		}
 		if loser != "" {
-			if err := db.updateUserStats(ctx, loser, false, false); err != nil {
+			if err := updateUserStats(ctx, tx, loser, false, false, finished); err != nil {
 				return err
 			}
 		}
 	} else if game.Result == "draw" {
 		// Both players get a draw
-		if err := db.updateUserStats(ctx, game.Player1, false, true); err != nil {
+		if err := updateUserStats(ctx, tx, game.Player1, false, true, finished); err != nil {
 			return err
 		}
 		if game.Player2 != "" {
-			if err := db.updateUserStats(ctx, game.Player2, false, true); err != nil {
+			if err := updateUserStats(ctx, tx, game.Player2, false, true, finished); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	if err := updateRatings(ctx, tx, game); err != nil {
+		return fmt.Errorf("failed to update ratings: %w", err)
+	}
+	if game.FinishedAt != nil {
+		if err := updateSeasonStats(ctx, tx, game, *game.FinishedAt); err != nil {
+			return fmt.Errorf("failed to update season stats: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
-// updateUserStats updates win/loss/draw counts for a user
-func (db *DB) updateUserStats(ctx context.Context, username string, won, drawn bool) error {
+// updateUserStats updates win/loss/draw counts for a user, who last played
+// at playedAt, the game's finish time. Guests keep no stats.
+func updateUserStats(ctx context.Context, tx pgx.Tx, username string, won, drawn bool, playedAt time.Time) error {
 	var query string
 	if won {
-		query = `UPDATE users SET games_won = games_won + 1 WHERE username = $1`
+		query = `UPDATE users SET games_won = games_won + 1, current_streak = current_streak + 1, last_played_at = $2 WHERE username = $1 AND (password_hash IS NOT NULL OR is_bot = TRUE)`
 	} else if drawn {
-		query = `UPDATE users SET games_drawn = games_drawn + 1 WHERE username = $1`
+		query = `UPDATE users SET games_drawn = games_drawn + 1, current_streak = 0, last_played_at = $2 WHERE username = $1 AND (password_hash IS NOT NULL OR is_bot = TRUE)`
 	} else {
-		query = `UPDATE users SET games_lost = games_lost + 1 WHERE username = $1`
+		query = `UPDATE users SET games_lost = games_lost + 1, current_streak = 0, last_played_at = $2 WHERE username = $1 AND (password_hash IS NOT NULL OR is_bot = TRUE)`
 	}
 	
-	_, err := db.pool.Exec(ctx, query, username)
+	_, err := tx.Exec(ctx, query, username, playedAt)
 	return err
 }
 
-// GetLeaderboard returns top players by wins
-func (db *DB) GetLeaderboard(ctx context.Context, limit int) ([]User, error) {
-	query := `
-		SELECT id, username, is_bot, games_won, games_lost, games_drawn, created_at
+// updateRatings applies a game's result to both players' ratings and
+// records the change in rating_history. Only rated games between two rated
+// players that ended in a win or a draw count; a game already rated is
+// left alone.
+func updateRatings(ctx context.Context, tx pgx.Tx, game *GameRecord) error {
+	if !game.Rated || game.Player2 == "" || game.Player1 == game.Player2 {
+		return nil
+	}
+
+	score, decided := gameScore(game)
+	if !decided {
+		return nil
+	}
+
+	var rated bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM rating_history WHERE game_id = $1)`, game.ID).Scan(&rated)
+	if err != nil || rated {
+		return err
+	}
+
+	// Lock both rows in a fixed order so concurrent saves cannot deadlock
+	rows, err := tx.Query(ctx, `
+		SELECT username, rating, rating_deviation, rating_volatility
 		FROM users
-		WHERE is_bot = FALSE
-		ORDER BY games_won DESC, games_lost ASC
-		LIMIT $1
+		WHERE username IN ($1, $2) AND (password_hash IS NOT NULL OR is_bot = TRUE)
+		ORDER BY username
+		FOR UPDATE
+	`, game.Player1, game.Player2)
+	if err != nil {
+		return err
+	}
+	ratings := make(map[string]rating.Rating, 2)
+	for rows.Next() {
+		var username string
+		var r rating.Rating
+		if err := rows.Scan(&username, &r.Rating, &r.Deviation, &r.Volatility); err != nil {
+			rows.Close()
+			return err
+		}
+		ratings[username] = r
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(ratings) < 2 {
+		return nil // a guest took part
+	}
+
+	r1, r2 := ratings[game.Player1], ratings[game.Player2]
+	updated := map[string]rating.Rating{
+		game.Player1: rating.Update(r1, r2, score),
+		game.Player2: rating.Update(r2, r1, rating.Win-score),
+	}
+
+	for username, after := range updated {
+		before := ratings[username]
+		_, err := tx.Exec(ctx, `
+			UPDATE users SET rating = $2, rating_deviation = $3, rating_volatility = $4
+			WHERE username = $1
+		`, username, after.Rating, after.Deviation, after.Volatility)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO rating_history (game_id, username, rating_before, rating_after, deviation_before, deviation_after)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, game.ID, username, before.Rating, after.Rating, before.Deviation, after.Deviation)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gameScore returns Player1's score in a game; decided is false for a game
+// that ended without a winner or a draw
+func gameScore(game *GameRecord) (score float64, decided bool) {
+	switch {
+	case game.Winner != nil && *game.Winner == game.Player1:
+		return rating.Win, true
+	case game.Winner != nil && *game.Winner == game.Player2:
+		return rating.Loss, true
+	case game.Result == "draw":
+		return rating.Draw, true
+	}
+	return 0, false
+}
+
+// Leaderboard orderings
+const (
+	SortByWins    = "wins"
+	SortByRating  = "rating"
+	SortByWinRate = "win_rate"
+	SortByStreak  = "streak"
+	SortByGames   = "games"
+)
+
+// leaderboardOrder maps each ordering to its ORDER BY clause. Ranking by
+// rating puts provisional ratings after established ones. Ties are broken
+// by username, so that every player has a rank of their own.
+var leaderboardOrder = map[string]string{
+	SortByWins:    `games_won DESC, games_lost ASC`,
+	SortByRating:  fmt.Sprintf(`rating_deviation > %v, rating DESC, games_won DESC`, rating.ProvisionalDeviation),
+	SortByWinRate: `games_won::float / GREATEST(games_won + games_lost + games_drawn, 1) DESC, games_won DESC`,
+	SortByStreak:  `current_streak DESC, games_won DESC`,
+	SortByGames:   `games_won + games_lost + games_drawn DESC, games_won DESC`,
+}
+
+// Who each leaderboard ranks: registered players, and bot accounts but not
+// the built-in bot, which has no account
+const (
+	playerBoard = `is_bot = FALSE AND password_hash IS NOT NULL`
+	botBoard    = `is_bot = TRUE AND api_key_hash IS NOT NULL`
+)
+
+// IsLeaderboardSort reports whether sortBy names a leaderboard ordering
+func IsLeaderboardSort(sortBy string) bool {
+	_, ok := leaderboardOrder[sortBy]
+	return ok
+}
+
+// LeaderboardScope narrows a leaderboard to a season, to the games
+// finished from From up to To, or both. The zero scope is the lifetime
+// leaderboard.
+type LeaderboardScope struct {
+	Season   string // ratings and stats of this season instead of lifetime ones
+	From, To time.Time
+}
+
+// LeaderboardQuery picks a page of a leaderboard
+type LeaderboardQuery struct {
+	LeaderboardScope
+	SortBy      string
+	MinGames    int       // players with fewer games within the scope are left out
+	ActiveSince time.Time // players who have not finished a game since are left out, unless zero
+	After       string    // the last player of the previous page, empty for the first page
+	Limit       int
+}
+
+// GetLeaderboard returns a page of the registered players' leaderboard
+func (db *DB) GetLeaderboard(ctx context.Context, q LeaderboardQuery) ([]User, error) {
+	return db.leaderboard(ctx, playerBoard, q)
+}
+
+// GetBotLeaderboard returns a page of the bot accounts' leaderboard
+func (db *DB) GetBotLeaderboard(ctx context.Context, q LeaderboardQuery) ([]User, error) {
+	return db.leaderboard(ctx, botBoard, q)
+}
+
+// GetLeaderboardAround returns username's place on the registered players'
+// leaderboard, with up to q.Limit players on either side; q.After is
+// ignored. It returns ErrUserNotFound if username is not on the
+// leaderboard.
+func (db *DB) GetLeaderboardAround(ctx context.Context, q LeaderboardQuery, username string) ([]User, error) {
+	var args []any
+	ranked, err := rankLeaderboard(playerBoard, q, &args)
+	if err != nil {
+		return nil, err
+	}
+	query := ranked + `
+		SELECT ` + userColumns + `, board.rank
+		FROM ranked board, (SELECT rank FROM ranked WHERE username = ` + bind(&args, username) + `) player
+		WHERE board.rank BETWEEN player.rank - ` + bind(&args, q.Limit) + ` AND player.rank + ` + bind(&args, q.Limit) + `
+		ORDER BY board.rank
 	`
-	
-	rows, err := db.pool.Query(ctx, query, limit)
+
+	users, err := db.rankedUsers(ctx, query, args)
+	if err == nil && len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return users, err
+}
+
+// leaderboard returns a page of the leaderboard of the users matching
+// where. It returns ErrUserNotFound if q.After is not on the leaderboard.
+func (db *DB) leaderboard(ctx context.Context, where string, q LeaderboardQuery) ([]User, error) {
+	var args []any
+	ranked, err := rankLeaderboard(where, q, &args)
+	if err != nil {
+		return nil, err
+	}
+	filters := len(args)
+
+	after := `0`
+	if q.After != "" {
+		after = `(SELECT rank FROM ranked WHERE username = ` + bind(&args, q.After) + `)`
+	}
+	query := ranked + `
+		SELECT ` + userColumns + `, rank
+		FROM ranked
+		WHERE rank > ` + after + `
+		ORDER BY rank
+		LIMIT ` + bind(&args, q.Limit) + `
+	`
+
+	users, err := db.rankedUsers(ctx, query, args)
+	if err != nil || len(users) > 0 || q.After == "" {
+		return users, err
+	}
+
+	// An empty page is either the end of the leaderboard or a cursor that
+	// is not on it
+	args = args[:filters]
+	var found bool
+	err = db.pool.QueryRow(ctx, ranked+` SELECT EXISTS (SELECT 1 FROM ranked WHERE username = `+bind(&args, q.After)+`)`, args...).Scan(&found)
+	if err == nil && !found {
+		err = ErrUserNotFound
+	}
+	return nil, err
+}
+
+// rankLeaderboard returns a WITH clause defining ranked, the users within
+// q's scope who match where and q's filters, with their rank in q's order.
+// Its parameters are appended to args.
+func rankLeaderboard(where string, q LeaderboardQuery, args *[]any) (string, error) {
+	order, ok := leaderboardOrder[q.SortBy]
+	if !ok {
+		return "", fmt.Errorf("unknown leaderboard sort %q", q.SortBy)
+	}
+
+	source := leaderboardSource(q.LeaderboardScope, args)
+	if q.MinGames > 0 {
+		where += ` AND games_won + games_lost + games_drawn >= ` + bind(args, q.MinGames)
+	}
+	if !q.ActiveSince.IsZero() {
+		where += ` AND last_played_at >= ` + bind(args, q.ActiveSince)
+	}
+
+	return `
+		WITH ranked AS (
+			SELECT ` + userColumns + `, ROW_NUMBER() OVER (ORDER BY ` + order + `, username) AS rank
+			FROM ` + source + ` board
+			WHERE ` + where + `
+		)`, nil
+}
+
+// rankedUsers runs a query selecting userColumns and a rank
+func (db *DB) rankedUsers(ctx context.Context, query string, args []any) ([]User, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -206,16 +785,7 @@ func (db *DB) GetLeaderboard(ctx context.Context, limit int) ([]User, error) {
 	var users []User
 	for rows.Next() {
 		var user User
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.IsBot,
-			&user.GamesWon,
-			&user.GamesLost,
-			&user.GamesDrawn,
-			&user.CreatedAt,
-		)
-		if err != nil {
+		if err := scanUser(rows, &user, &user.Rank); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -224,27 +794,119 @@ func (db *DB) GetLeaderboard(ctx context.Context, limit int) ([]User, error) {
 	return users, rows.Err()
 }
 
+// bind appends a query parameter to args and returns its placeholder
+func bind(args *[]any, v any) string {
+	*args = append(*args, v)
+	return fmt.Sprintf("$%d", len(*args))
+}
+
+// leaderboardSource returns the table or subquery a leaderboard within
+// scope ranks, with the columns of users, appending its parameters to args.
+// Within a window of time the stats are counted from the games finished in
+// it, while ratings stay those of the season or lifetime.
+func leaderboardSource(scope LeaderboardScope, args *[]any) string {
+	if scope.From.IsZero() && scope.To.IsZero() {
+		if scope.Season == "" {
+			return `users`
+		}
+		return `(
+			SELECT u.id, u.username, u.is_bot, s.games_won, s.games_lost, s.games_drawn,
+				s.rating, s.rating_deviation, u.current_streak, u.last_played_at, u.created_at,
+				u.password_hash, u.api_key_hash
+			FROM season_stats s
+			JOIN users u ON u.username = s.username
+			WHERE s.season = ` + bind(args, scope.Season) + `
+		)`
+	}
+
+	window := `finished_at >= ` + bind(args, scope.From)
+	if !scope.To.IsZero() {
+		window += ` AND finished_at < ` + bind(args, scope.To)
+	}
+	window += ` AND player2 IS NOT NULL AND player1 <> player2`
+
+	ratings, join, group := `u.rating, u.rating_deviation`, ``, `u.id`
+	if scope.Season != "" {
+		ratings = fmt.Sprintf(`COALESCE(s.rating, %v) AS rating, COALESCE(s.rating_deviation, %v) AS rating_deviation`,
+			rating.DefaultRating, rating.DefaultDeviation)
+		join = `LEFT JOIN season_stats s ON s.season = ` + bind(args, scope.Season) + ` AND s.username = u.username`
+		group += `, s.rating, s.rating_deviation`
+	}
+
+	return `(
+		SELECT u.id, u.username, u.is_bot,
+			COUNT(*) FILTER (WHERE p.outcome = 'won') AS games_won,
+			COUNT(*) FILTER (WHERE p.outcome = 'lost') AS games_lost,
+			COUNT(*) FILTER (WHERE p.outcome = 'drawn') AS games_drawn,
+			` + ratings + `,
+			u.current_streak, u.last_played_at, u.created_at, u.password_hash, u.api_key_hash
+		FROM (
+			SELECT player1 AS username, CASE
+				WHEN winner = player1 THEN 'won' WHEN winner = player2 THEN 'lost' WHEN result = 'draw' THEN 'drawn'
+			END AS outcome
+			FROM games WHERE ` + window + `
+			UNION ALL
+			SELECT player2, CASE
+				WHEN winner = player2 THEN 'won' WHEN winner = player1 THEN 'lost' WHEN result = 'draw' THEN 'drawn'
+			END
+			FROM games WHERE ` + window + `
+		) p
+		JOIN users u ON u.username = p.username
+		` + join + `
+		WHERE p.outcome IS NOT NULL
+		GROUP BY ` + group + `
+	)`
+}
+
+// GetRatingHistory returns a user's most recent rating changes, newest first
+func (db *DB) GetRatingHistory(ctx context.Context, username string, limit int) ([]RatingChange, error) {
+	query := `
+		SELECT h.game_id,
+			CASE WHEN g.player1 = h.username THEN g.player2 ELSE g.player1 END,
+			h.rating_before, h.rating_after, h.deviation_after, h.created_at
+		FROM rating_history h
+		JOIN games g ON g.id = h.game_id
+		WHERE h.username = $1
+		ORDER BY h.created_at DESC, h.id DESC
+		LIMIT $2
+	`
+
+	rows, err := db.pool.Query(ctx, query, username, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []RatingChange
+	for rows.Next() {
+		var change RatingChange
+		var before, after, deviation float64
+		err := rows.Scan(&change.GameID, &change.Opponent, &before, &after, &deviation, &change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		change.RatingBefore = int(math.Round(before))
+		change.RatingAfter = int(math.Round(after))
+		change.RatingDeviation = int(math.Round(deviation))
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
 // GetUserStats returns statistics for a specific user
 func (db *DB) GetUserStats(ctx context.Context, username string) (*User, error) {
 	query := `
-		SELECT id, username, is_bot, games_won, games_lost, games_drawn, created_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE username = $1
 	`
 	
 	var user User
-	err := db.pool.QueryRow(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.IsBot,
-		&user.GamesWon,
-		&user.GamesLost,
-		&user.GamesDrawn,
-		&user.CreatedAt,
-	)
+	err := scanUser(db.pool.QueryRow(ctx, query, username), &user)
 	
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrUserNotFound
 	}
 	
 	return &user, err
@@ -253,7 +915,7 @@ func (db *DB) GetUserStats(ctx context.Context, username string) (*User, error)
 // GetRecentGames returns recent games
 func (db *DB) GetRecentGames(ctx context.Context, limit int) ([]GameRecord, error) {
 	query := `
-		SELECT id, player1, player2, winner, result, board_state, started_at, finished_at, created_at
+		SELECT id, player1, COALESCE(player2, ''), winner, result, board_state, queue, rated, started_at, finished_at, created_at
 		FROM games
 		ORDER BY created_at DESC
 		LIMIT $1
@@ -277,6 +939,8 @@ func (db *DB) GetRecentGames(ctx context.Context, limit int) ([]GameRecord, erro
 			&game.Winner,
 			&game.Result,
 			&boardJSON,
+			&game.Queue,
+			&game.Rated,
 			&game.StartedAt,
 			&game.FinishedAt,
 			&game.CreatedAt,
@@ -298,7 +962,7 @@ func (db *DB) GetRecentGames(ctx context.Context, limit int) ([]GameRecord, erro
 // GetUserGames returns games for a specific user
 func (db *DB) GetUserGames(ctx context.Context, username string, limit int) ([]GameRecord, error) {
 	query := `
-		SELECT id, player1, player2, winner, result, board_state, started_at, finished_at, created_at
+		SELECT id, player1, COALESCE(player2, ''), winner, result, board_state, queue, rated, started_at, finished_at, created_at
 		FROM games
 		WHERE player1 = $1 OR player2 = $1
 		ORDER BY created_at DESC
@@ -323,6 +987,8 @@ func (db *DB) GetUserGames(ctx context.Context, username string, limit int) ([]G
 			&game.Winner,
 			&game.Result,
 			&boardJSON,
+			&game.Queue,
+			&game.Rated,
 			&game.StartedAt,
 			&game.FinishedAt,
 			&game.CreatedAt,
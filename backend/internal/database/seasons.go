@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/yourusername/4-in-a-row/internal/rating"
+)
+
+// ErrSeasonNotFound is returned when no season has the requested name
+var ErrSeasonNotFound = errors.New("season not found")
+
+// Season is a competitive season. Games finished from StartsAt up to
+// EndsAt count towards it, and its ratings start afresh. ArchivedAt is set
+// once its final standings have been archived.
+type Season struct {
+	Name       string     `json:"name"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     time.Time  `json:"ends_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// SeasonStanding is a player's place in the final standings of a season
+type SeasonStanding struct {
+	Rank            int    `json:"rank"`
+	Username        string `json:"username"`
+	IsBot           bool   `json:"is_bot"`
+	GamesWon        int    `json:"games_won"`
+	GamesLost       int    `json:"games_lost"`
+	GamesDrawn      int    `json:"games_drawn"`
+	Rating          int    `json:"rating"`
+	RatingDeviation int    `json:"rating_deviation"`
+	Provisional     bool   `json:"provisional"`
+}
+
+// SyncSeasons saves the configured seasons, updating the dates of those
+// already known. Seasons no longer configured are kept with their stats.
+func (db *DB) SyncSeasons(ctx context.Context, seasons []Season) error {
+	for _, s := range seasons {
+		_, err := db.pool.Exec(ctx, `
+			INSERT INTO seasons (name, starts_at, ends_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO UPDATE SET starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at
+		`, s.Name, s.StartsAt, s.EndsAt)
+		if err != nil {
+			return fmt.Errorf("failed to save season %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListSeasons returns every season, the earliest first
+func (db *DB) ListSeasons(ctx context.Context) ([]Season, error) {
+	rows, err := db.pool.Query(ctx, `SELECT name, starts_at, ends_at, archived_at FROM seasons ORDER BY starts_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []Season
+	for rows.Next() {
+		var s Season
+		if err := rows.Scan(&s.Name, &s.StartsAt, &s.EndsAt, &s.ArchivedAt); err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, s)
+	}
+
+	return seasons, rows.Err()
+}
+
+// ArchiveSeason freezes the final standings of a season that has ended.
+// Players and bots are ranked separately, by season rating as on the
+// rating leaderboard. Archiving a season again changes nothing.
+func (db *DB) ArchiveSeason(ctx context.Context, name string) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var archived *time.Time
+	err = tx.QueryRow(ctx, `SELECT archived_at FROM seasons WHERE name = $1 FOR UPDATE`, name).Scan(&archived)
+	if err == pgx.ErrNoRows {
+		return ErrSeasonNotFound
+	}
+	if err != nil || archived != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO season_standings (season, rank, username, is_bot, games_won, games_lost, games_drawn, rating, rating_deviation)
+		SELECT s.season,
+			ROW_NUMBER() OVER (PARTITION BY u.is_bot ORDER BY `+seasonRatingOrder+`),
+			s.username, u.is_bot, s.games_won, s.games_lost, s.games_drawn, s.rating, s.rating_deviation
+		FROM season_stats s
+		JOIN users u ON u.username = s.username
+		WHERE s.season = $1
+	`, name)
+	if err != nil {
+		return fmt.Errorf("failed to archive standings: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE seasons SET archived_at = CURRENT_TIMESTAMP WHERE name = $1`, name); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// seasonRatingOrder ranks season_stats rows s like SortByRating
+var seasonRatingOrder = fmt.Sprintf(`s.rating_deviation > %v, s.rating DESC, s.games_won DESC, s.username`, rating.ProvisionalDeviation)
+
+// GetSeasonStandings returns the archived standings of a season, of bots
+// or of players, by rank. They are empty until the season is archived.
+func (db *DB) GetSeasonStandings(ctx context.Context, season string, bots bool) ([]SeasonStanding, error) {
+	rows, err := db.pool.Query(ctx, `
+		SELECT rank, username, is_bot, games_won, games_lost, games_drawn, rating, rating_deviation
+		FROM season_standings
+		WHERE season = $1 AND is_bot = $2
+		ORDER BY rank
+	`, season, bots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []SeasonStanding
+	for rows.Next() {
+		var s SeasonStanding
+		var r, rd float64
+		if err := rows.Scan(&s.Rank, &s.Username, &s.IsBot, &s.GamesWon, &s.GamesLost, &s.GamesDrawn, &r, &rd); err != nil {
+			return nil, err
+		}
+		s.Rating = int(math.Round(r))
+		s.RatingDeviation = int(math.Round(rd))
+		s.Provisional = rd > rating.ProvisionalDeviation
+		standings = append(standings, s)
+	}
+
+	return standings, rows.Err()
+}
+
+// updateSeasonStats counts a saved game towards the season it finished in
+// at finished, if any: the win, loss or draw of each player with an
+// account, and for rated games their season ratings
+func updateSeasonStats(ctx context.Context, tx pgx.Tx, game *GameRecord, finished time.Time) error {
+	if game.Player2 == "" || game.Player1 == game.Player2 {
+		return nil
+	}
+	score, decided := gameScore(game)
+	if !decided {
+		return nil
+	}
+
+	var season string
+	err := tx.QueryRow(ctx, `SELECT name FROM seasons WHERE starts_at <= $1 AND ends_at > $1`, finished).Scan(&season)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO season_stats (season, username)
+		SELECT $1, username FROM users
+		WHERE username IN ($2, $3) AND (password_hash IS NOT NULL OR is_bot = TRUE)
+		ON CONFLICT (season, username) DO NOTHING
+	`, season, game.Player1, game.Player2)
+	if err != nil {
+		return err
+	}
+
+	// Lock both rows in a fixed order so concurrent saves cannot deadlock
+	rows, err := tx.Query(ctx, `
+		SELECT username, rating, rating_deviation, rating_volatility
+		FROM season_stats
+		WHERE season = $1 AND username IN ($2, $3)
+		ORDER BY username
+		FOR UPDATE
+	`, season, game.Player1, game.Player2)
+	if err != nil {
+		return err
+	}
+	ratings := make(map[string]rating.Rating, 2)
+	for rows.Next() {
+		var username string
+		var r rating.Rating
+		if err := rows.Scan(&username, &r.Rating, &r.Deviation, &r.Volatility); err != nil {
+			rows.Close()
+			return err
+		}
+		ratings[username] = r
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	scores := map[string]float64{game.Player1: score, game.Player2: rating.Win - score}
+	opponents := map[string]string{game.Player1: game.Player2, game.Player2: game.Player1}
+	for username, before := range ratings {
+		after := before
+		opponent, rated := ratings[opponents[username]]
+		if game.Rated && rated {
+			after = rating.Update(before, opponent, scores[username])
+		}
+
+		var won, lost, drawn int
+		switch scores[username] {
+		case rating.Win:
+			won = 1
+		case rating.Loss:
+			lost = 1
+		default:
+			drawn = 1
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE season_stats SET
+				games_won = games_won + $3, games_lost = games_lost + $4, games_drawn = games_drawn + $5,
+				rating = $6, rating_deviation = $7, rating_volatility = $8
+			WHERE season = $1 AND username = $2
+		`, season, username, won, lost, drawn, after.Rating, after.Deviation, after.Volatility)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTournamentNotFound is returned when no tournament has the requested ID
+var ErrTournamentNotFound = errors.New("tournament not found")
+
+// Tournament is a tournament's settings and progress, see package
+// tournament for the meaning of its fields
+type Tournament struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Format       string     `json:"format"`
+	Status       string     `json:"status"`
+	Rounds       int        `json:"rounds"`        // 0 until a Swiss tournament starts without a set number, and for knockouts
+	CurrentRound int        `json:"current_round"` // 0 before the first round, and for knockouts
+	BestOf       int        `json:"best_of"`       // games a knockout match is played over
+	Rated        bool       `json:"rated"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// TournamentPlayer is a player registered for a tournament
+type TournamentPlayer struct {
+	Username     string    `json:"username"`
+	IsBot        bool      `json:"is_bot"`
+	Rating       int       `json:"rating"` // when they registered, used for seeding
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// TournamentPairing is one game of a tournament round. Player2 is empty for
+// a bye and Result stays empty until the game is over.
+type TournamentPairing struct {
+	Round   int    `json:"round"`
+	Board   int    `json:"board"`
+	Player1 string `json:"player1"`
+	Player2 string `json:"player2,omitempty"`
+	GameID  string `json:"game_id,omitempty"`
+	Result  string `json:"result,omitempty"`
+}
+
+// BracketGame is one game of a knockout match. Player1 moves first and
+// Result stays empty until the game is over.
+type BracketGame struct {
+	Match   int    `json:"match"`
+	Game    int    `json:"game"` // 1 for a match's first game
+	Player1 string `json:"player1"`
+	Player2 string `json:"player2"`
+	GameID  string `json:"game_id,omitempty"`
+	Result  string `json:"result,omitempty"`
+}
+
+const tournamentColumns = `id, name, format, status, rounds, current_round, best_of, rated, created_at, started_at, finished_at`
+
+func scanTournament(row pgx.Row, t *Tournament) error {
+	return row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Format,
+		&t.Status,
+		&t.Rounds,
+		&t.CurrentRound,
+		&t.BestOf,
+		&t.Rated,
+		&t.CreatedAt,
+		&t.StartedAt,
+		&t.FinishedAt,
+	)
+}
+
+// CreateTournament saves a new tournament and sets its CreatedAt
+func (db *DB) CreateTournament(ctx context.Context, t *Tournament) error {
+	query := `
+		INSERT INTO tournaments (id, name, format, status, rounds, current_round, best_of, rated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	return db.pool.QueryRow(ctx, query,
+		t.ID, t.Name, t.Format, t.Status, t.Rounds, t.CurrentRound, t.BestOf, t.Rated,
+	).Scan(&t.CreatedAt)
+}
+
+// AddTournamentPlayer registers a player for a tournament and sets their
+// RegisteredAt. Registering twice keeps the first registration.
+func (db *DB) AddTournamentPlayer(ctx context.Context, tournamentID string, p *TournamentPlayer) error {
+	query := `
+		INSERT INTO tournament_players (tournament_id, username, is_bot, rating)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tournament_id, username) DO UPDATE SET username = EXCLUDED.username
+		RETURNING registered_at
+	`
+
+	return db.pool.QueryRow(ctx, query, tournamentID, p.Username, p.IsBot, p.Rating).Scan(&p.RegisteredAt)
+}
+
+// RemoveTournamentPlayer withdraws a player from a tournament
+func (db *DB) RemoveTournamentPlayer(ctx context.Context, tournamentID, username string) error {
+	_, err := db.pool.Exec(ctx, `DELETE FROM tournament_players WHERE tournament_id = $1 AND username = $2`, tournamentID, username)
+	return err
+}
+
+// SaveTournamentProgress records a tournament's status and round together
+// with new or updated pairings and bracket games, in one transaction
+func (db *DB) SaveTournamentProgress(ctx context.Context, t *Tournament, pairings []TournamentPairing, games []BracketGame) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE tournaments
+		SET status = $2, rounds = $3, current_round = $4, started_at = $5, finished_at = $6
+		WHERE id = $1
+	`, t.ID, t.Status, t.Rounds, t.CurrentRound, t.StartedAt, t.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update tournament: %w", err)
+	}
+
+	for _, p := range pairings {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO tournament_pairings (tournament_id, round, board, player1, player2, game_id, result)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+			ON CONFLICT (tournament_id, round, board) DO UPDATE SET
+				game_id = EXCLUDED.game_id,
+				result = EXCLUDED.result
+		`, t.ID, p.Round, p.Board, p.Player1, p.Player2, p.GameID, p.Result)
+		if err != nil {
+			return fmt.Errorf("failed to save pairing: %w", err)
+		}
+	}
+
+	for _, g := range games {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO tournament_bracket_games (tournament_id, match_id, game_number, player1, player2, game_id, result)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+			ON CONFLICT (tournament_id, match_id, game_number) DO UPDATE SET
+				game_id = EXCLUDED.game_id,
+				result = EXCLUDED.result
+		`, t.ID, g.Match, g.Game, g.Player1, g.Player2, g.GameID, g.Result)
+		if err != nil {
+			return fmt.Errorf("failed to save bracket game: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTournament returns a tournament with its players, in registration
+// order, and its pairings, by round and board
+func (db *DB) GetTournament(ctx context.Context, id string) (*Tournament, []TournamentPlayer, []TournamentPairing, error) {
+	var t Tournament
+	err := scanTournament(db.pool.QueryRow(ctx, `SELECT `+tournamentColumns+` FROM tournaments WHERE id = $1`, id), &t)
+	if err == pgx.ErrNoRows {
+		return nil, nil, nil, ErrTournamentNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err := db.pool.Query(ctx, `
+		SELECT username, is_bot, rating, registered_at
+		FROM tournament_players
+		WHERE tournament_id = $1
+		ORDER BY registered_at, username
+	`, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var players []TournamentPlayer
+	for rows.Next() {
+		var p TournamentPlayer
+		if err := rows.Scan(&p.Username, &p.IsBot, &p.Rating, &p.RegisteredAt); err != nil {
+			rows.Close()
+			return nil, nil, nil, err
+		}
+		players = append(players, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows, err = db.pool.Query(ctx, `
+		SELECT round, board, player1, COALESCE(player2, ''), COALESCE(game_id, ''), result
+		FROM tournament_pairings
+		WHERE tournament_id = $1
+		ORDER BY round, board
+	`, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	var pairings []TournamentPairing
+	for rows.Next() {
+		var p TournamentPairing
+		if err := rows.Scan(&p.Round, &p.Board, &p.Player1, &p.Player2, &p.GameID, &p.Result); err != nil {
+			return nil, nil, nil, err
+		}
+		pairings = append(pairings, p)
+	}
+
+	return &t, players, pairings, rows.Err()
+}
+
+// GetBracketGames returns the games played in a knockout tournament's
+// matches, by match and game
+func (db *DB) GetBracketGames(ctx context.Context, id string) ([]BracketGame, error) {
+	rows, err := db.pool.Query(ctx, `
+		SELECT match_id, game_number, player1, player2, COALESCE(game_id, ''), result
+		FROM tournament_bracket_games
+		WHERE tournament_id = $1
+		ORDER BY match_id, game_number
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []BracketGame
+	for rows.Next() {
+		var g BracketGame
+		if err := rows.Scan(&g.Match, &g.Game, &g.Player1, &g.Player2, &g.GameID, &g.Result); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+
+	return games, rows.Err()
+}
+
+// ListTournaments returns the most recently created tournaments with one of
+// statuses, or with any status if none are given. A limit of 0 returns all
+// of them.
+func (db *DB) ListTournaments(ctx context.Context, limit int, statuses ...string) ([]Tournament, error) {
+	query := `
+		SELECT ` + tournamentColumns + `
+		FROM tournaments
+		WHERE cardinality($1::text[]) = 0 OR status = ANY($1)
+		ORDER BY created_at DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := db.pool.Query(ctx, query, statuses, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tournaments []Tournament
+	for rows.Next() {
+		var t Tournament
+		if err := scanTournament(rows, &t); err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+
+	return tournaments, rows.Err()
+}
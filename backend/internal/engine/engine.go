@@ -0,0 +1,297 @@
+// Package engine runs an external Connect Four engine as a child process,
+// so engines written in any language can take the bot's seat. The server
+// talks to it over stdin and stdout with a line-based protocol modelled on
+// UCI:
+//
+//	server: c4               engine may answer "id name <name>", then: c4ok
+//	server: position 4453    moves so far as columns 1-7, 0 for a skipped turn
+//	server: go 28500         milliseconds left to answer
+//	engine: bestmove 3       the column to play, 1-7
+//	server: quit             on shutdown
+//
+// Other lines from the engine, such as "info ...", are ignored, and its
+// stderr goes to the server log. An engine that times out, exits or answers
+// nonsense is killed and restarted on a later move, waiting longer after
+// each consecutive failure.
+package engine
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/4-in-a-row/internal/clock"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+var (
+	ErrTimeout     = errors.New("engine timed out")
+	ErrExited      = errors.New("engine exited")
+	ErrBadReply    = errors.New("invalid engine reply")
+	ErrUnavailable = errors.New("engine unavailable")
+	ErrClosed      = errors.New("engine closed")
+)
+
+const (
+	handshakeTimeout = 10 * time.Second
+	quitTimeout      = time.Second
+	maxRestartDelay  = time.Minute
+)
+
+// Config describes how to run an engine
+type Config struct {
+	Name        string        // username the engine plays under
+	Command     string        // executable to run
+	Args        []string      // arguments to pass it
+	MoveTimeout time.Duration // longest wait for a move, whatever the time left
+}
+
+// Process is an engine running as a child process. It implements
+// game.Engine; requests from concurrent games are answered one at a time.
+type Process struct {
+	cfg   Config
+	clock clock.Clock
+
+	mu       sync.Mutex // held for a whole request; guards the fields below
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    <-chan string // stdout, closed once the process has exited
+	failures int           // consecutive failures, reset by a good reply
+	retryAt  time.Time     // no restart before this after a failure
+	closed   bool
+}
+
+// New returns an engine that starts its process on the first move it is
+// asked for. Timeouts are measured against clk.
+func New(cfg Config, clk clock.Clock) *Process {
+	return &Process{cfg: cfg, clock: clk}
+}
+
+// Name returns the username the engine plays under
+func (p *Process) Name() string {
+	return p.cfg.Name
+}
+
+// BestMove sends the position in snap to the engine and waits for its move,
+// at most timeLeft or the configured MoveTimeout
+func (p *Process) BestMove(ctx context.Context, snap *game.Snapshot, timeLeft time.Duration) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return -1, ErrClosed
+	}
+	if err := p.start(ctx); err != nil {
+		return -1, err
+	}
+
+	limit := timeLeft
+	if p.cfg.MoveTimeout > 0 && p.cfg.MoveTimeout < limit {
+		limit = p.cfg.MoveTimeout
+	}
+	if limit <= 0 {
+		return -1, ErrTimeout
+	}
+
+	if err := p.send("position "+MoveString(snap.Moves), fmt.Sprintf("go %d", limit.Milliseconds())); err != nil {
+		return -1, p.fail(err)
+	}
+
+	args, err := p.await(ctx, "bestmove", limit)
+	if err != nil {
+		return -1, p.fail(err)
+	}
+	if len(args) == 0 {
+		return -1, p.fail(fmt.Errorf("%w: bestmove without a column", ErrBadReply))
+	}
+	column, err := strconv.Atoi(args[0])
+	if err != nil || column < 1 || column > game.Columns {
+		return -1, p.fail(fmt.Errorf("%w: bestmove %q", ErrBadReply, args[0]))
+	}
+
+	p.failures = 0
+	return column - 1, nil
+}
+
+// Close asks the engine to quit and kills it if it does not
+func (p *Process) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if p.cmd == nil {
+		return nil
+	}
+
+	p.send("quit")
+	select {
+	case <-drain(p.lines):
+	case <-p.clock.After(quitTimeout):
+	}
+	p.stop()
+	return nil
+}
+
+// MoveString writes moves in the protocol's notation: one digit per turn,
+// the 1-based column or 0 for a turn lost to the timer
+func MoveString(moves []game.Move) string {
+	var b strings.Builder
+	for _, move := range moves {
+		if move.Skipped {
+			b.WriteByte('0')
+			continue
+		}
+		b.WriteString(strconv.Itoa(move.Column + 1))
+	}
+	return b.String()
+}
+
+// start launches the process and completes the handshake, unless it is
+// already running. The caller must hold p.mu.
+func (p *Process) start(ctx context.Context) error {
+	if p.cmd != nil {
+		return nil
+	}
+	if now := p.clock.Now(); now.Before(p.retryAt) {
+		return fmt.Errorf("%w: restarting in %v", ErrUnavailable, p.retryAt.Sub(now).Round(time.Second))
+	}
+
+	cmd := exec.Command(p.cfg.Command, p.cfg.Args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return p.fail(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return p.fail(err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return p.fail(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return p.fail(fmt.Errorf("%w: %v", ErrUnavailable, err))
+	}
+
+	lines := make(chan string, 16)
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	go func() {
+		defer readers.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Printf("Engine %s: %s", p.cfg.Name, scanner.Text())
+		}
+	}()
+	go func() {
+		readers.Wait()
+		if err := cmd.Wait(); err != nil {
+			log.Printf("Engine %s exited: %v", p.cfg.Name, err)
+		}
+	}()
+
+	p.cmd, p.stdin, p.lines = cmd, stdin, lines
+
+	if err := p.send("c4"); err != nil {
+		return p.fail(err)
+	}
+	if _, err := p.await(ctx, "c4ok", handshakeTimeout); err != nil {
+		return p.fail(err)
+	}
+
+	log.Printf("Engine %s started (pid %d)", p.cfg.Name, cmd.Process.Pid)
+	return nil
+}
+
+// send writes protocol lines to the engine. The caller must hold p.mu.
+func (p *Process) send(lines ...string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(p.stdin, line+"\n"); err != nil {
+			return fmt.Errorf("%w: %v", ErrExited, err)
+		}
+	}
+	return nil
+}
+
+// await reads lines until one starting with keyword and returns the words
+// after it. The caller must hold p.mu.
+func (p *Process) await(ctx context.Context, keyword string, timeout time.Duration) ([]string, error) {
+	deadline := p.clock.After(timeout)
+	for {
+		select {
+		case line, ok := <-p.lines:
+			if !ok {
+				return nil, ErrExited
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if fields[0] == keyword {
+				return fields[1:], nil
+			}
+			if len(fields) > 2 && fields[0] == "id" && fields[1] == "name" {
+				log.Printf("Engine %s identifies as %s", p.cfg.Name, strings.Join(fields[2:], " "))
+			}
+		case <-deadline:
+			return nil, fmt.Errorf("%w: no %s after %v", ErrTimeout, keyword, timeout)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", ErrTimeout, ctx.Err())
+		}
+	}
+}
+
+// fail kills the process after err and holds off restarting it for a delay
+// that doubles with each consecutive failure. The caller must hold p.mu.
+func (p *Process) fail(err error) error {
+	p.stop()
+
+	p.failures++
+	delay := time.Second << (p.failures - 1)
+	if delay > maxRestartDelay || delay <= 0 {
+		delay = maxRestartDelay
+	}
+	p.retryAt = p.clock.Now().Add(delay)
+
+	log.Printf("Engine %s failed (%d in a row), restarting after %v: %v", p.cfg.Name, p.failures, delay, err)
+	return err
+}
+
+// stop kills the process if it is running. The caller must hold p.mu.
+func (p *Process) stop() {
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	drain(p.lines) // lets the stdout reader finish so the process is reaped
+	p.cmd, p.stdin, p.lines = nil, nil, nil
+}
+
+// drain discards lines until the channel is closed, then closes the
+// returned channel
+func drain(lines <-chan string) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range lines {
+		}
+		close(done)
+	}()
+	return done
+}
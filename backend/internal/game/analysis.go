@@ -0,0 +1,42 @@
+package game
+
+import "fmt"
+
+// Analysis is the built-in engine's view of a position
+type Analysis struct {
+	ToMove     CellState
+	BestColumn int
+	Scores     map[int]float64 // valid column -> score for ToMove, higher is better
+}
+
+// AnalyzeMoves replays columns from the empty board, player 1 first, and
+// scores every legal reply. It fails with ErrGameNotInProgress once the
+// position is already decided.
+func AnalyzeMoves(moves []int) (*Analysis, error) {
+	board := NewBoard()
+	toMove := Player1
+
+	for i, column := range moves {
+		if _, err := board.DropDisc(column, toMove); err != nil {
+			return nil, fmt.Errorf("move %d (column %d): %w", i+1, column, err)
+		}
+		if board.CheckWin(toMove) || board.IsFull() {
+			return nil, fmt.Errorf("%w: decided after move %d", ErrGameNotInProgress, i+1)
+		}
+		toMove = opponentOf(toMove)
+	}
+
+	bot := NewBot(toMove)
+	return &Analysis{
+		ToMove:     toMove,
+		BestColumn: bot.GetBestMove(board),
+		Scores:     bot.ColumnScores(board),
+	}, nil
+}
+
+func opponentOf(seat CellState) CellState {
+	if seat == Player1 {
+		return Player2
+	}
+	return Player1
+}
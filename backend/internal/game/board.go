@@ -1,7 +1,6 @@
 package game
 
 import (
-	"errors"
 	"fmt"
 )
 
@@ -29,7 +28,7 @@ func NewBoard() *Board {
 // DropDisc drops a disc into the specified column
 func (b *Board) DropDisc(column int, player CellState) (int, error) {
 	if column < 0 || column >= Columns {
-		return -1, errors.New("invalid column")
+		return -1, ErrInvalidMove
 	}
 
 	// Find the lowest available row in the column
@@ -40,7 +39,7 @@ func (b *Board) DropDisc(column int, player CellState) (int, error) {
 		}
 	}
 
-	return -1, errors.New("column is full")
+	return -1, ErrColumnFull
 }
 
 // IsValidMove checks if a move is valid
@@ -14,20 +14,63 @@ const (
 	CenterBonus = 3
 )
 
+// Difficulty weakens the bot for players it would otherwise outclass. The
+// zero value plays at full strength.
+type Difficulty struct {
+	Depth    int     // minimax search depth, MaxDepth if zero
+	Mistakes float64 // chance of playing a random column instead of searching
+}
+
+// difficulties maps rating bands to bot strength, weakest first. Players
+// rated above the last band face the full-strength bot.
+var difficulties = []struct {
+	below      float64
+	difficulty Difficulty
+}{
+	{1100, Difficulty{Depth: 1, Mistakes: 0.4}},
+	{1300, Difficulty{Depth: 2, Mistakes: 0.25}},
+	{1450, Difficulty{Depth: 3, Mistakes: 0.15}},
+	{1600, Difficulty{Depth: 4, Mistakes: 0.05}},
+}
+
+// DifficultyFor returns the bot strength suited to an opponent rated rating
+func DifficultyFor(rating float64) Difficulty {
+	for _, band := range difficulties {
+		if rating < band.below {
+			return band.difficulty
+		}
+	}
+	return Difficulty{}
+}
+
 type Bot struct {
 	player   CellState
 	opponent CellState
+	depth    int
+	mistakes float64
 	rand     *rand.Rand
 }
 
+// NewBot returns a full-strength bot playing player
 func NewBot(player CellState) *Bot {
+	return NewBotWithDifficulty(player, Difficulty{})
+}
+
+// NewBotWithDifficulty returns a bot playing player at the given strength
+func NewBotWithDifficulty(player CellState, difficulty Difficulty) *Bot {
 	opponent := Player1
 	if player == Player1 {
 		opponent = Player2
 	}
+	depth := difficulty.Depth
+	if depth <= 0 || depth > MaxDepth {
+		depth = MaxDepth
+	}
 	return &Bot{
 		player:   player,
 		opponent: opponent,
+		depth:    depth,
+		mistakes: difficulty.Mistakes,
 		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
@@ -39,6 +82,11 @@ func (bot *Bot) GetBestMove(board *Board) int {
 		return -1
 	}
 
+	// A weakened bot sometimes plays without looking
+	if bot.mistakes > 0 && bot.rand.Float64() < bot.mistakes {
+		return validMoves[bot.rand.Intn(len(validMoves))]
+	}
+
 	// Check for immediate winning move
 	for _, col := range validMoves {
 		testBoard := board.Copy()
@@ -62,14 +110,7 @@ func (bot *Bot) GetBestMove(board *Board) int {
 	bestMoves := []int{}
 
 	for _, col := range validMoves {
-		testBoard := board.Copy()
-		row, _ := testBoard.DropDisc(col, bot.player)
-		
-		// Evaluate immediate position
-		score := float64(bot.evaluateWindow(testBoard, row, col))
-		
-		// Add minimax score
-		score += bot.minimax(testBoard, MaxDepth-1, math.Inf(-1), math.Inf(1), false)
+		score := bot.scoreColumn(board, col)
 
 		if score > bestScore {
 			bestScore = score
@@ -87,6 +128,28 @@ func (bot *Bot) GetBestMove(board *Board) int {
 	return validMoves[0]
 }
 
+// ColumnScores scores every valid column for the bot's side the way
+// GetBestMove ranks them; higher is better
+func (bot *Bot) ColumnScores(board *Board) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, col := range board.GetValidMoves() {
+		scores[col] = bot.scoreColumn(board, col)
+	}
+	return scores
+}
+
+// scoreColumn evaluates dropping a disc in col
+func (bot *Bot) scoreColumn(board *Board, col int) float64 {
+	testBoard := board.Copy()
+	row, _ := testBoard.DropDisc(col, bot.player)
+
+	// Evaluate immediate position
+	score := float64(bot.evaluateWindow(testBoard, row, col))
+
+	// Add minimax score
+	return score + bot.minimax(testBoard, bot.depth-1, math.Inf(-1), math.Inf(1), false)
+}
+
 // minimax implements the minimax algorithm with alpha-beta pruning
 func (bot *Bot) minimax(board *Board, depth int, alpha, beta float64, maximizing bool) float64 {
 	// Terminal conditions
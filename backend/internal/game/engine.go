@@ -0,0 +1,18 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+// Engine chooses moves for a hosted player in place of the built-in Bot,
+// for example an external program run by package engine. Implementations
+// must be safe for concurrent use by several games.
+type Engine interface {
+	// Name is the username the engine plays under
+	Name() string
+
+	// BestMove returns the column to play in snap for the player to move.
+	// timeLeft is what remains of the turn; replies after it are useless.
+	BestMove(ctx context.Context, snap *Snapshot, timeLeft time.Duration) (int, error)
+}
@@ -9,4 +9,27 @@ var (
 	ErrNotYourTurn       = errors.New("not your turn")
 	ErrInvalidMove       = errors.New("invalid move")
 	ErrColumnFull        = errors.New("column is full")
+	ErrPlayerNotInGame   = errors.New("player not found in game")
+	ErrNotInGame         = errors.New("not in a game")
+	ErrGameFull          = errors.New("game already has two players")
+	ErrSelfMatch         = errors.New("cannot play against yourself")
+	ErrGameNotOpen       = errors.New("game cannot be joined by ID")
+
+	// Reconnect
+	ErrSessionNotFound  = errors.New("session not found or expired")
+	ErrReconnectExpired = errors.New("reconnect window expired")
+
+	// Matchmaking
+	ErrServerDraining = errors.New("server is draining")
+	ErrMatchFailed    = errors.New("failed to join matched game")
+	ErrUnknownQueue   = errors.New("unknown matchmaking queue")
+	ErrQueueExists    = errors.New("matchmaking queue already exists")
+	ErrNotSearching   = errors.New("not searching for a match")
+	ErrAlreadyPlaying = errors.New("already searching or playing; reconnect with your session token")
+
+	// Bot
+	ErrNoBot      = errors.New("game does not have a bot")
+	ErrNotBotTurn = errors.New("not bot's turn")
+	ErrNoBotMove  = errors.New("bot could not find valid move")
+	ErrTurnOver   = errors.New("bot's turn ran out before it could move")
 )
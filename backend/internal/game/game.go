@@ -1,12 +1,13 @@
 package game
 
 import (
-	"encoding/json"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/4-in-a-row/internal/clock"
 )
 
 type GameStatus string
@@ -32,289 +33,599 @@ type Player struct {
 	Username       string     `json:"username"`
 	SessionToken   string     `json:"session_token"`
 	IsBot          bool       `json:"is_bot"`
+	Hosted         bool       `json:"-"` // moved by the server, not by a client
+	Engine         string     `json:"-"` // engine moving a hosted player, "" for the built-in Bot
+	Difficulty     Difficulty `json:"-"` // strength of the built-in Bot
+	Rating         float64    `json:"-"` // rating used for matchmaking, see package rating
 	Connected      bool       `json:"connected"`
 	LastHeartbeat  time.Time  `json:"-"`
 	DisconnectedAt *time.Time `json:"-"`
 }
 
+// GameOptions are the settings a game is created with
+type GameOptions struct {
+	Queue       string        // matchmaking queue the game was paired in, "" if none
+	TurnTimeout time.Duration // a turn is skipped after this long
+	Rated       bool          // whether the result changes the players' ratings
+	Open        bool          // anyone may take the second seat by the game's ID
+}
+
+// Move is one turn of a game: a disc dropped by Seat, or a turn Seat lost
+// to the turn timer
+type Move struct {
+	Seat    CellState `json:"seat"`
+	Column  int       `json:"column"`
+	Row     int       `json:"row"`
+	Skipped bool      `json:"skipped,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// Game is an actor: every field below the ID is owned by the goroutine
+// started in NewGame and is only touched by commands running on it. Other
+// goroutines read the game through Snapshot or Subscribe.
 type Game struct {
-	ID             string     `json:"id"`
-	Player1        *Player    `json:"player1"`
-	Player2        *Player    `json:"player2"`
-	Board          *Board     `json:"board"`
-	CurrentTurn    CellState  `json:"current_turn"`
-	Status         GameStatus `json:"status"`
-	Winner         *Player    `json:"winner,omitempty"`
-	Result         GameResult `json:"result,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	StartedAt      *time.Time `json:"started_at,omitempty"`
-	FinishedAt     *time.Time `json:"finished_at,omitempty"`
-	LastMoveAt     time.Time  `json:"last_move_at"`
-	TurnStartedAt  time.Time  `json:"turn_started_at"`
-	TurnTimeoutSec int        `json:"turn_timeout_sec"`
-	Bot            *Bot       `json:"-"`
-	mu             sync.RWMutex
-}
-
-func NewGame(player1 *Player) *Game {
-	now := time.Now()
-	return &Game{
-		ID:             uuid.New().String(),
-		Player1:        player1,
-		Board:          NewBoard(),
-		CurrentTurn:    Player1,
-		Status:         StatusWaiting,
-		CreatedAt:      now,
-		LastMoveAt:     now,
-		TurnStartedAt:  now,
-		TurnTimeoutSec: 30, // 30 seconds per turn
+	ID string
+
+	player1       *Player
+	player2       *Player
+	board         *Board
+	currentTurn   CellState
+	status        GameStatus
+	winner        *Player
+	result        GameResult
+	createdAt     time.Time
+	startedAt     *time.Time
+	finishedAt    *time.Time
+	lastMoveAt    time.Time
+	turnStartedAt time.Time
+	turnTimeout   time.Duration
+	queue         string
+	rated         bool
+	open          bool
+	moves         []Move
+	bot           *Bot
+	version       uint64
+	clock         clock.Clock
+
+	commands chan command
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	snapshot    atomic.Pointer[Snapshot]
+	subsMu      sync.Mutex
+	subscribers map[chan *Snapshot]struct{}
+}
+
+// command is a unit of work run on the game goroutine. fn reports whether
+// it changed the game state, in which case a new snapshot is published.
+type command struct {
+	fn   func() bool
+	done chan struct{}
+}
+
+// NewGame creates a game for player1 with opts and starts its goroutine.
+// The clock drives every timestamp the game records.
+func NewGame(player1 *Player, opts GameOptions, clk clock.Clock) *Game {
+	now := clk.Now()
+	g := &Game{
+		ID:            uuid.New().String(),
+		player1:       player1,
+		board:         NewBoard(),
+		currentTurn:   Player1,
+		status:        StatusWaiting,
+		createdAt:     now,
+		lastMoveAt:    now,
+		turnStartedAt: now,
+		turnTimeout:   opts.TurnTimeout,
+		queue:         opts.Queue,
+		rated:         opts.Rated,
+		open:          opts.Open,
+		clock:         clk,
+		commands:      make(chan command),
+		stopped:       make(chan struct{}),
+		subscribers:   make(map[chan *Snapshot]struct{}),
+	}
+	g.snapshot.Store(g.takeSnapshot())
+
+	go g.run()
+
+	return g
+}
+
+// run is the game's actor loop; it serializes every command sent to the game
+func (g *Game) run() {
+	for {
+		select {
+		case cmd := <-g.commands:
+			if cmd.fn() {
+				g.publish()
+			}
+			close(cmd.done)
+		case <-g.stopped:
+			g.closeSubscribers()
+			return
+		}
+	}
+}
+
+// do runs fn on the game goroutine and waits for it to complete. It returns
+// false if the game has been stopped and fn was not run.
+func (g *Game) do(fn func() bool) bool {
+	cmd := command{fn: fn, done: make(chan struct{})}
+	select {
+	case g.commands <- cmd:
+	case <-g.stopped:
+		return false
+	}
+	<-cmd.done
+	return true
+}
+
+// Stop terminates the game goroutine and closes all subscriptions
+func (g *Game) Stop() {
+	g.stopOnce.Do(func() {
+		close(g.stopped)
+	})
+}
+
+// Snapshot returns the most recently published state of the game
+func (g *Game) Snapshot() *Snapshot {
+	return g.snapshot.Load()
+}
+
+// Subscribe returns a channel receiving every snapshot published after the
+// call, starting with the current one. Slow subscribers only ever miss
+// intermediate snapshots, never the latest. The returned function cancels
+// the subscription.
+func (g *Game) Subscribe() (<-chan *Snapshot, func()) {
+	ch := make(chan *Snapshot, 1)
+
+	g.subsMu.Lock()
+	select {
+	case <-g.stopped:
+		g.subsMu.Unlock()
+		close(ch)
+		return ch, func() {}
+	default:
+	}
+	g.subscribers[ch] = struct{}{}
+	ch <- g.Snapshot()
+	g.subsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			g.subsMu.Lock()
+			defer g.subsMu.Unlock()
+			if _, ok := g.subscribers[ch]; ok {
+				delete(g.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+}
+
+// publish stores a new snapshot and hands it to every subscriber
+func (g *Game) publish() {
+	g.version++
+	snap := g.takeSnapshot()
+	g.snapshot.Store(snap)
+
+	g.subsMu.Lock()
+	defer g.subsMu.Unlock()
+
+	for ch := range g.subscribers {
+		// Replace any snapshot the subscriber has not consumed yet
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- snap
+	}
+}
+
+func (g *Game) closeSubscribers() {
+	g.subsMu.Lock()
+	defer g.subsMu.Unlock()
+
+	for ch := range g.subscribers {
+		delete(g.subscribers, ch)
+		close(ch)
 	}
 }
 
 // AddPlayer2 adds the second player to the game
-func (g *Game) AddPlayer2(player2 *Player) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	g.Player2 = player2
-	now := time.Now()
-	g.StartedAt = &now
-	g.Status = StatusInProgress
-	g.TurnStartedAt = now // Start timer for first turn
-
-	// Initialize bot if player2 is a bot
-	if player2.IsBot {
-		g.Bot = NewBot(Player2)
+func (g *Game) AddPlayer2(player2 *Player) error {
+	var err error
+	ok := g.do(func() bool {
+		if g.status != StatusWaiting || g.player2 != nil {
+			err = ErrGameFull
+			return false
+		}
+		if player2.Username == g.player1.Username {
+			err = ErrSelfMatch
+			return false
+		}
+
+		g.player2 = player2
+		now := g.clock.Now()
+		g.startedAt = &now
+		g.status = StatusInProgress
+		g.turnStartedAt = now // Start timer for first turn
+
+		// Initialize bot if the server moves for player2
+		if player2.Hosted {
+			g.bot = NewBotWithDifficulty(Player2, player2.Difficulty)
+		}
+		return true
+	})
+	if !ok {
+		return ErrGameNotFound
 	}
+	return err
 }
 
 // MakeMove processes a move in the game
 func (g *Game) MakeMove(playerID string, column int) (int, error) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	row := -1
+	var err error
+	ok := g.do(func() bool {
+		row, err = g.makeMove(playerID, column)
+		return err == nil
+	})
+	if !ok {
+		return -1, ErrGameNotFound
+	}
+	return row, err
+}
 
+func (g *Game) makeMove(playerID string, column int) (int, error) {
 	// Validate game state
-	if g.Status != StatusInProgress {
+	if g.status != StatusInProgress {
 		return -1, ErrGameNotInProgress
 	}
 
 	// Validate player turn
-	var currentPlayer CellState
-	if g.Player1.ID == playerID {
-		currentPlayer = Player1
-	} else if g.Player2.ID == playerID {
-		currentPlayer = Player2
-	} else {
+	currentPlayer := g.seatOf(playerID)
+	if currentPlayer == Empty {
 		return -1, ErrInvalidPlayer
 	}
 
-	if currentPlayer != g.CurrentTurn {
+	if currentPlayer != g.currentTurn {
 		return -1, ErrNotYourTurn
 	}
 
 	// Make the move
-	row, err := g.Board.DropDisc(column, currentPlayer)
+	row, err := g.board.DropDisc(column, currentPlayer)
 	if err != nil {
 		return -1, err
 	}
 
-	now := time.Now()
-	g.LastMoveAt = now
+	now := g.clock.Now()
+	g.lastMoveAt = now
+	g.moves = append(g.moves, Move{Seat: currentPlayer, Column: column, Row: row, At: now})
 
 	// Check for win
-	if g.Board.CheckWin(currentPlayer) {
+	if g.board.CheckWin(currentPlayer) {
 		g.finishGame(currentPlayer)
 		return row, nil
 	}
 
 	// Check for draw
-	if g.Board.IsFull() {
+	if g.board.IsFull() {
 		g.finishGameDraw()
 		return row, nil
 	}
 
 	// Switch turn and reset turn timer
-	if g.CurrentTurn == Player1 {
-		g.CurrentTurn = Player2
-	} else {
-		g.CurrentTurn = Player1
-	}
-	g.TurnStartedAt = now // Reset turn timer for next player
+	g.switchTurn(now)
 
 	return row, nil
 }
 
-// GetBotMove gets the next move from the bot
+// GetBotMove gets the next move from the built-in bot
 func (g *Game) GetBotMove() int {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-
-	if g.Bot == nil {
-		return -1
-	}
-
-	return g.Bot.GetBestMove(g.Board)
+	column := -1
+	g.do(func() bool {
+		if g.bot != nil {
+			column = g.bot.GetBestMove(g.board)
+		}
+		return false
+	})
+	return column
 }
 
 // SkipTurn skips the current player's turn due to timeout
 func (g *Game) SkipTurn() {
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	g.do(func() bool {
+		return g.skipTurn()
+	})
+}
 
-	if g.Status != StatusInProgress {
-		return
+func (g *Game) skipTurn() bool {
+	if g.status != StatusInProgress {
+		return false
 	}
 
-	now := time.Now()
-	g.LastMoveAt = now
+	now := g.clock.Now()
+	g.lastMoveAt = now
+	g.moves = append(g.moves, Move{Seat: g.currentTurn, Column: -1, Row: -1, Skipped: true, At: now})
 
 	// Switch turn without making a move
-	if g.CurrentTurn == Player1 {
-		g.CurrentTurn = Player2
+	g.switchTurn(now)
+
+	log.Printf("Turn skipped for game %s, now %v's turn", g.ID, g.currentTurn)
+	return true
+}
+
+// switchTurn hands the move to the other player and resets the turn timer
+func (g *Game) switchTurn(now time.Time) {
+	if g.currentTurn == Player1 {
+		g.currentTurn = Player2
 	} else {
-		g.CurrentTurn = Player1
+		g.currentTurn = Player1
 	}
-	g.TurnStartedAt = now // Reset turn timer for next player
-
-	log.Printf("Turn skipped for game %s, now %v's turn", g.ID, g.CurrentTurn)
+	g.turnStartedAt = now
 }
 
 // finishGame marks the game as finished with a winner
 func (g *Game) finishGame(winner CellState) {
-	now := time.Now()
-	g.FinishedAt = &now
-	g.Status = StatusFinished
+	now := g.clock.Now()
+	g.finishedAt = &now
+	g.status = StatusFinished
 
 	if winner == Player1 {
-		g.Winner = g.Player1
-		g.Result = ResultPlayer1Win
+		g.winner = g.player1
+		g.result = ResultPlayer1Win
 	} else {
-		g.Winner = g.Player2
-		g.Result = ResultPlayer2Win
+		g.winner = g.player2
+		g.result = ResultPlayer2Win
 	}
 }
 
 // finishGameDraw marks the game as finished in a draw
 func (g *Game) finishGameDraw() {
-	now := time.Now()
-	g.FinishedAt = &now
-	g.Status = StatusFinished
-	g.Result = ResultDraw
+	now := g.clock.Now()
+	g.finishedAt = &now
+	g.status = StatusFinished
+	g.result = ResultDraw
+}
+
+// Resign ends the game as a loss for playerID. A game still waiting for an
+// opponent cannot be resigned; the manager discards it instead.
+func (g *Game) Resign(playerID string) error {
+	var err error
+	ok := g.do(func() bool {
+		seat := g.seatOf(playerID)
+		switch {
+		case seat == Empty:
+			err = ErrInvalidPlayer
+			return false
+		case g.status != StatusInProgress:
+			err = ErrGameNotInProgress
+			return false
+		}
+
+		if seat == Player1 {
+			g.finishGame(Player2)
+		} else {
+			g.finishGame(Player1)
+		}
+		log.Printf("Player %s resigned game %s", playerID, g.ID)
+		return true
+	})
+	if !ok {
+		return ErrGameNotFound
+	}
+	return err
 }
 
 // AbandonGame marks the game as abandoned
 func (g *Game) AbandonGame(disconnectedPlayerID string) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	g.do(func() bool {
+		return g.abandon(disconnectedPlayerID)
+	})
+}
 
-	if g.Status == StatusFinished {
-		return
+func (g *Game) abandon(disconnectedPlayerID string) bool {
+	if g.status == StatusFinished || g.status == StatusAbandoned {
+		return false
 	}
 
-	now := time.Now()
-	g.FinishedAt = &now
-	g.Status = StatusAbandoned
-	g.Result = ResultAbandoned
+	now := g.clock.Now()
+	g.finishedAt = &now
+	g.status = StatusAbandoned
+	g.result = ResultAbandoned
 
 	// Set winner as the other player
-	if g.Player1.ID == disconnectedPlayerID && g.Player2 != nil {
-		g.Winner = g.Player2
-		if !g.Player2.IsBot {
-			g.Result = ResultPlayer2Win
+	if g.player1.ID == disconnectedPlayerID && g.player2 != nil {
+		g.winner = g.player2
+		if !g.player2.Hosted {
+			g.result = ResultPlayer2Win
 		}
-	} else if g.Player2 != nil && g.Player2.ID == disconnectedPlayerID {
-		g.Winner = g.Player1
-		g.Result = ResultPlayer1Win
+	} else if g.player2 != nil && g.player2.ID == disconnectedPlayerID {
+		g.winner = g.player1
+		g.result = ResultPlayer1Win
 	}
+	return true
+}
+
+// UpdateHeartbeat updates the last heartbeat time for a player
+func (g *Game) UpdateHeartbeat(playerID string) {
+	g.do(func() bool {
+		player := g.playerByID(playerID)
+		if player == nil {
+			return false
+		}
+		player.LastHeartbeat = g.clock.Now()
+		if player.Connected {
+			return false
+		}
+		player.Connected = true
+		return true
+	})
+}
+
+// SetPlayerDisconnected marks a player as disconnected
+func (g *Game) SetPlayerDisconnected(playerID string) {
+	g.do(func() bool {
+		player := g.playerByID(playerID)
+		if player == nil {
+			return false
+		}
+		now := g.clock.Now()
+		player.Connected = false
+		player.DisconnectedAt = &now
+		return true
+	})
 }
 
-// GetCurrentPlayer returns the player whose turn it is
-func (g *Game) GetCurrentPlayer() *Player {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
+// Reconnect marks the player holding sessionToken as connected again,
+// provided they have been gone for no longer than window. It returns a copy
+// of the reconnected player.
+func (g *Game) Reconnect(sessionToken string, window time.Duration) (*Player, error) {
+	var player Player
+	var err error
+	ok := g.do(func() bool {
+		var p *Player
+		if g.player1.SessionToken == sessionToken {
+			p = g.player1
+		} else if g.player2 != nil && g.player2.SessionToken == sessionToken {
+			p = g.player2
+		}
+
+		if p == nil {
+			err = ErrPlayerNotInGame
+			return false
+		}
+
+		// Check if reconnect window is still open
+		var away time.Duration
+		if p.DisconnectedAt != nil {
+			away = g.clock.Since(*p.DisconnectedAt)
+			if away > window {
+				log.Printf("Reconnect window expired for player %s (elapsed: %v)", p.Username, away)
+				err = ErrReconnectExpired
+				return false
+			}
+		}
+
+		p.Connected = true
+		p.LastHeartbeat = g.clock.Now()
+		p.DisconnectedAt = nil
+		player = *p
 
-	if g.CurrentTurn == Player1 {
-		return g.Player1
+		log.Printf("Player %s reconnected to game %s (was disconnected for %v)", p.Username, g.ID, away)
+		return true
+	})
+	if !ok {
+		return nil, ErrGameNotFound
 	}
-	return g.Player2
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
 }
 
-// IsPlayerTurn checks if it's the specified player's turn
-func (g *Game) IsPlayerTurn(playerID string) bool {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
+// ExpireHeartbeats abandons the game on behalf of any connected player moved
+// by a client whose last heartbeat is older than timeout
+func (g *Game) ExpireHeartbeats(now time.Time, timeout time.Duration) {
+	g.do(func() bool {
+		if g.status != StatusInProgress {
+			return false
+		}
 
-	currentPlayer := g.GetCurrentPlayer()
-	return currentPlayer != nil && currentPlayer.ID == playerID
+		for _, p := range []*Player{g.player1, g.player2} {
+			if p == nil || p.Hosted || !p.Connected {
+				continue
+			}
+			if now.Sub(p.LastHeartbeat) > timeout {
+				log.Printf("Player %s timed out in game %s", p.Username, g.ID)
+				return g.abandon(p.ID)
+			}
+		}
+		return false
+	})
 }
 
-// UpdateHeartbeat updates the last heartbeat time for a player
-func (g *Game) UpdateHeartbeat(playerID string) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	if g.Player1.ID == playerID {
-		g.Player1.LastHeartbeat = time.Now()
-		g.Player1.Connected = true
-	} else if g.Player2 != nil && g.Player2.ID == playerID {
-		g.Player2.LastHeartbeat = time.Now()
-		g.Player2.Connected = true
-	}
+// ExpireDisconnected forfeits the game for a disconnected player, human or
+// bot account, who has not reconnected within window
+func (g *Game) ExpireDisconnected(now time.Time, window time.Duration) {
+	g.do(func() bool {
+		if g.status != StatusInProgress {
+			return false
+		}
+
+		for _, p := range []*Player{g.player1, g.player2} {
+			if p == nil || p.Hosted || p.Connected || p.DisconnectedAt == nil {
+				continue
+			}
+			if now.Sub(*p.DisconnectedAt) > window {
+				log.Printf("Player %s did not reconnect to game %s in time", p.Username, g.ID)
+				return g.abandon(p.ID)
+			}
+		}
+		return false
+	})
 }
 
-// SetPlayerDisconnected marks a player as disconnected
-func (g *Game) SetPlayerDisconnected(playerID string) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	now := time.Now()
-	if g.Player1.ID == playerID {
-		g.Player1.Connected = false
-		g.Player1.DisconnectedAt = &now
-	} else if g.Player2 != nil && g.Player2.ID == playerID {
-		g.Player2.Connected = false
-		g.Player2.DisconnectedAt = &now
+// CheckTurnTimer forfeits the game for the player to move once nothing has
+// happened for longer than inactivity, and otherwise skips their turn once
+// the per-turn timeout has elapsed
+func (g *Game) CheckTurnTimer(now time.Time, inactivity time.Duration) {
+	g.do(func() bool {
+		if g.status != StatusInProgress {
+			return false
+		}
+
+		// Check for total inactivity timeout (forfeit)
+		inactivityElapsed := now.Sub(g.lastMoveAt)
+		if inactivityElapsed > inactivity {
+			if p := g.currentPlayer(); p != nil {
+				log.Printf("Inactivity timeout in game %s, forfeiting game (elapsed: %v)", g.ID, inactivityElapsed)
+				return g.abandon(p.ID)
+			}
+		}
+
+		// Check for turn timeout
+		turnElapsed := now.Sub(g.turnStartedAt)
+		if turnElapsed > g.turnTimeout {
+			log.Printf("Turn timeout in game %s, skipping turn (elapsed: %v)", g.ID, turnElapsed)
+			return g.skipTurn()
+		}
+		return false
+	})
+}
+
+// seatOf returns which side playerID is playing, or Empty if neither
+func (g *Game) seatOf(playerID string) CellState {
+	if g.player1.ID == playerID {
+		return Player1
+	}
+	if g.player2 != nil && g.player2.ID == playerID {
+		return Player2
 	}
+	return Empty
 }
 
-// ToJSON converts the game to JSON
-func (g *Game) ToJSON() ([]byte, error) {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-
-	type GameJSON struct {
-		ID             string     `json:"id"`
-		Player1        *Player    `json:"player1"`
-		Player2        *Player    `json:"player2"`
-		Board          [][]int    `json:"board"`
-		CurrentTurn    int        `json:"current_turn"`
-		Status         GameStatus `json:"status"`
-		Winner         *Player    `json:"winner,omitempty"`
-		Result         GameResult `json:"result,omitempty"`
-		CreatedAt      time.Time  `json:"created_at"`
-		StartedAt      *time.Time `json:"started_at,omitempty"`
-		FinishedAt     *time.Time `json:"finished_at,omitempty"`
-		LastMoveAt     time.Time  `json:"last_move_at"`
-		TurnStartedAt  time.Time  `json:"turn_started_at"`
-		TurnTimeoutSec int        `json:"turn_timeout_sec"`
+func (g *Game) playerByID(playerID string) *Player {
+	switch g.seatOf(playerID) {
+	case Player1:
+		return g.player1
+	case Player2:
+		return g.player2
 	}
+	return nil
+}
 
-	gameJSON := GameJSON{
-		ID:             g.ID,
-		Player1:        g.Player1,
-		Player2:        g.Player2,
-		Board:          g.Board.ToArray(),
-		CurrentTurn:    int(g.CurrentTurn),
-		Status:         g.Status,
-		Winner:         g.Winner,
-		Result:         g.Result,
-		CreatedAt:      g.CreatedAt,
-		StartedAt:      g.StartedAt,
-		FinishedAt:     g.FinishedAt,
-		LastMoveAt:     g.LastMoveAt,
-		TurnStartedAt:  g.TurnStartedAt,
-		TurnTimeoutSec: g.TurnTimeoutSec,
+func (g *Game) currentPlayer() *Player {
+	if g.currentTurn == Player1 {
+		return g.player1
 	}
+	return g.player2
+}
 
-	return json.Marshal(gameJSON)
+// ToJSON converts the game to JSON
+func (g *Game) ToJSON() ([]byte, error) {
+	return g.Snapshot().ToJSON()
 }
@@ -4,163 +4,387 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/yourusername/4-in-a-row/internal/clock"
 	"github.com/yourusername/4-in-a-row/internal/database"
 	"github.com/yourusername/4-in-a-row/internal/kafka"
+	"github.com/yourusername/4-in-a-row/internal/rating"
 )
 
+// Manager indexes the games in memory. Manager.mu only guards the maps and
+// the callback; game state itself is owned by each game's goroutine.
 type Manager struct {
-	games         map[string]*Game
-	playerGames   map[string]string // playerID -> gameID
-	sessionGames  map[string]string // sessionToken -> gameID
-	mu            sync.RWMutex
-	db            *database.DB
-	kafkaProducer *kafka.Producer
-	onGameUpdate  func(gameID string) // Callback when game state changes
+	games          map[string]*Game
+	playerGames    map[string]string // playerID -> gameID
+	sessionGames   map[string]string // sessionToken -> gameID
+	mu             sync.RWMutex
+	db             *database.DB
+	kafkaProducer  *kafka.Producer
+	rules          Rules
+	clock          clock.Clock
+	onGameUpdate   func(snap *Snapshot)   // Callback when game state changes
+	onGameRemoved  func(gameID string)    // Callback when a game leaves memory
+	onGameStarted  []func(snap *Snapshot) // Callbacks once both players are in a game
+	onGameFinished []func(snap *Snapshot) // Callbacks once a finished game is saved
+	engines        map[string]Engine      // by name, see RegisterEngine
+	done           <-chan struct{}        // closed when the manager is shut down
+	watchers       sync.WaitGroup         // one per game, see watchGame
 }
 
-func NewManager(db *database.DB, kafkaProducer *kafka.Producer) *Manager {
+// NewManager creates a game manager enforcing rules. All timeouts are
+// measured against clk.
+func NewManager(db *database.DB, kafkaProducer *kafka.Producer, rules Rules, clk clock.Clock) *Manager {
 	m := &Manager{
 		games:         make(map[string]*Game),
 		playerGames:   make(map[string]string),
 		sessionGames:  make(map[string]string),
+		engines:       make(map[string]Engine),
 		db:            db,
 		kafkaProducer: kafkaProducer,
+		rules:         rules,
+		clock:         clk,
 	}
 
+	return m
+}
+
+// Start launches the background timers that enforce heartbeats, turn
+// timeouts and finished-game retention. They stop when ctx is cancelled.
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	m.done = ctx.Done()
+	m.mu.Unlock()
+
 	// Start cleanup goroutine
-	go m.cleanupDisconnectedGames()
+	go m.cleanupDisconnectedGames(ctx)
 
 	// Start turn timer monitoring
-	go m.monitorTurnTimers()
-
-	return m
+	go m.monitorTurnTimers(ctx)
 }
 
 // SetGameUpdateCallback sets a callback function to be called when game state changes
-func (m *Manager) SetGameUpdateCallback(callback func(gameID string)) {
+func (m *Manager) SetGameUpdateCallback(callback func(snap *Snapshot)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.onGameUpdate = callback
 	log.Printf("SetGameUpdateCallback: callback registered successfully (callback is nil: %v)", callback == nil)
 }
 
-// CreateGame creates a new game with player1
-func (m *Manager) CreateGame(player1 *Player) *Game {
+// SetGameRemovedCallback sets a callback function to be called once a game has
+// been removed from memory
+func (m *Manager) SetGameRemovedCallback(callback func(gameID string)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.onGameRemoved = callback
+}
+
+// AddGameStartedCallback adds a callback to be called with the state of
+// every game once its second player has joined. The matchmaker may be
+// holding its lock, so the callback must not call into it.
+func (m *Manager) AddGameStartedCallback(callback func(snap *Snapshot)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onGameStarted = append(m.onGameStarted, callback)
+}
+
+// AddGameFinishedCallback adds a callback to be called with the final state
+// of every game once it has been saved, for subsystems that follow results,
+// such as tournaments
+func (m *Manager) AddGameFinishedCallback(callback func(snap *Snapshot)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onGameFinished = append(m.onGameFinished, callback)
+}
+
+// RegisterEngine makes an engine available to move hosted players whose
+// Engine field names it
+func (m *Manager) RegisterEngine(engine Engine) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.engines[engine.Name()] = engine
+}
+
+func (m *Manager) engine(name string) Engine {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.engines[name]
+}
+
+// IsHostedName reports whether username belongs to the built-in bot or a
+// registered engine, and so may not be taken by a client
+func (m *Manager) IsHostedName(username string) bool {
+	return username == BotUsername || m.engine(username) != nil
+}
+
+func (m *Manager) gameUpdateCallback() func(snap *Snapshot) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.onGameUpdate
+}
+
+// CreateGame creates a new rated game with player1 under the default rules,
+// which anyone may join by its ID with JoinOpenGame
+func (m *Manager) CreateGame(player1 *Player) *Game {
+	return m.CreateGameWithOptions(player1, GameOptions{TurnTimeout: m.rules.TurnTimeout, Rated: true, Open: true})
+}
+
+// CreateGameWithOptions creates a new game with player1 and opts
+func (m *Manager) CreateGameWithOptions(player1 *Player, opts GameOptions) *Game {
+	game := NewGame(player1, opts, m.clock)
 
-	game := NewGame(player1)
+	m.mu.Lock()
 	m.games[game.ID] = game
 	m.playerGames[player1.ID] = game.ID
 	if player1.SessionToken != "" {
 		m.sessionGames[player1.SessionToken] = game.ID
 	}
+	m.mu.Unlock()
+
+	m.watchers.Add(1)
+	go m.watchGame(game)
 
 	log.Printf("Game created: %s for player %s (session: %s)", game.ID, player1.Username, player1.SessionToken)
 
 	return game
 }
 
+// StartGame creates a game between two players arranged outside matchmaking,
+// such as a tournament pairing, and starts it straight away. A zero
+// opts.TurnTimeout means the default one.
+func (m *Manager) StartGame(player1, player2 *Player, opts GameOptions) (*Game, error) {
+	if opts.TurnTimeout == 0 {
+		opts.TurnTimeout = m.rules.TurnTimeout
+	}
+	game := m.CreateGameWithOptions(player1, opts)
+	if err := m.JoinGame(game.ID, player2); err != nil {
+		m.removeGame(game.ID)
+		return nil, err
+	}
+	return game, nil
+}
+
+// JoinOpenGame adds player2 to a game created with CreateGame. Games the
+// matchmaker or a tournament arranged cannot be joined this way.
+func (m *Manager) JoinOpenGame(gameID string, player2 *Player) error {
+	game, err := m.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+	if !game.Snapshot().Open {
+		return ErrGameNotOpen
+	}
+	return m.JoinGame(gameID, player2)
+}
+
 // JoinGame adds player2 to an existing game
 func (m *Manager) JoinGame(gameID string, player2 *Player) error {
-	// Acquire lock, capture state, then release before callbacks
-	m.mu.Lock()
-
-	log.Printf("JoinGame called: gameID=%s player2=%s callback_is_nil=%v", gameID, player2.Username, m.onGameUpdate == nil)
+	game, err := m.GetGame(gameID)
+	if err != nil {
+		return err
+	}
 
-	game, exists := m.games[gameID]
-	if !exists {
-		m.mu.Unlock()
-		return ErrGameNotFound
+	if err := game.AddPlayer2(player2); err != nil {
+		return err
 	}
 
-	game.AddPlayer2(player2)
+	m.mu.Lock()
 	m.playerGames[player2.ID] = gameID
 	if player2.SessionToken != "" {
 		m.sessionGames[player2.SessionToken] = gameID
 	}
-
-	log.Printf("Player %s joined game %s (session: %s)", player2.Username, gameID, player2.SessionToken)
-
-	// Capture state needed for Kafka event before releasing lock
 	activeGames := len(m.games)
 	totalPlayers := len(m.playerGames)
-
-	// Release lock before emitting events or calling callbacks to avoid deadlock
+	callbacks := m.onGameStarted
 	m.mu.Unlock()
 
-	// Emit game started event (uses captured state, no locking)
-	m.emitGameStartedEventWithState(game, activeGames, totalPlayers)
+	log.Printf("Player %s joined game %s (session: %s)", player2.Username, gameID, player2.SessionToken)
 
-	// Notify websocket layer that game state changed
-	if m.onGameUpdate != nil {
-		log.Printf("Triggering game update callback for game %s", gameID)
-		go m.onGameUpdate(gameID)
-	} else {
-		log.Printf("WARNING: onGameUpdate callback is nil for game %s", gameID)
+	// Emit game started event
+	snap := game.Snapshot()
+	m.emitGameStartedEventWithState(snap, activeGames, totalPlayers)
+	for _, callback := range callbacks {
+		callback(snap)
 	}
 
 	return nil
 }
 
+// watchGame follows a game's published snapshots for its whole life: it
+// notifies the update callback, schedules bot moves and finalises the game
+// once it is over. Every state change reaches clients through here.
+func (m *Manager) watchGame(game *Game) {
+	defer m.watchers.Done()
+
+	updates, unsubscribe := game.Subscribe()
+	defer unsubscribe()
+
+	var botTurn time.Time
+	for snap := range updates {
+		if callback := m.gameUpdateCallback(); callback != nil {
+			callback(snap)
+		}
+
+		if snap.IsOver() {
+			m.handleGameFinished(snap)
+			return
+		}
+
+		// Each turn has its own start time, so this triggers once per bot turn
+		if snap.IsBotTurn() && !snap.TurnStartedAt.Equal(botTurn) {
+			botTurn = snap.TurnStartedAt
+			go func(gameID string) {
+				if err := m.HandleBotMove(gameID); err != nil {
+					log.Printf("Bot move failed in game %s: %v", gameID, err)
+				}
+			}(snap.ID)
+		}
+	}
+}
+
 // ReconnectPlayer reconnects a player to their game using session token
 func (m *Manager) ReconnectPlayer(sessionToken string) (*Game, *Player, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	// Find game by session token
 	gameID, exists := m.sessionGames[sessionToken]
 	if !exists {
-		return nil, nil, errors.New("session not found or expired")
+		m.mu.Unlock()
+		return nil, nil, ErrSessionNotFound
 	}
 
 	game, exists := m.games[gameID]
 	if !exists {
 		// Clean up stale session
 		delete(m.sessionGames, sessionToken)
-		return nil, nil, errors.New("game no longer exists")
+		m.mu.Unlock()
+		return nil, nil, ErrGameNotFound
+	}
+	m.mu.Unlock()
+
+	player, err := game.Reconnect(sessionToken, m.rules.ReconnectWindow)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Find player in game
-	var player *Player
-	if game.Player1.SessionToken == sessionToken {
-		player = game.Player1
-	} else if game.Player2 != nil && game.Player2.SessionToken == sessionToken {
-		player = game.Player2
+	return game, player, nil
+}
+
+// NewPlayer creates a connected human player with a fresh session token
+// and the rating of a new player
+func (m *Manager) NewPlayer(username string) *Player {
+	return &Player{
+		ID:            uuid.New().String(),
+		Username:      username,
+		SessionToken:  uuid.New().String(),
+		IsBot:         false,
+		Connected:     true,
+		LastHeartbeat: m.clock.Now(),
+		Rating:        rating.DefaultRating,
+	}
+}
+
+// NewHostedBot creates a bot opponent moved by the server: the engine
+// registered as engineName, or the built-in Bot if engineName is empty
+func (m *Manager) NewHostedBot(engineName string) *Player {
+	username := BotUsername
+	if engineName != "" {
+		username = engineName
+	}
+	return &Player{
+		ID:            uuid.New().String(),
+		Username:      username,
+		IsBot:         true,
+		Hosted:        true,
+		Engine:        engineName,
+		Connected:     true,
+		LastHeartbeat: m.clock.Now(),
 	}
+}
+
+// NewBotPlayer creates a player for a bot account. Unlike the built-in bot
+// it is moved by its own client, which connects and heartbeats like a human.
+func (m *Manager) NewBotPlayer(username string) *Player {
+	player := m.NewPlayer(username)
+	player.IsBot = true
+	return player
+}
 
-	if player == nil {
-		return nil, nil, errors.New("player not found in game")
+// Resign ends a game as a loss for playerID. A game still waiting for an
+// opponent has no result, so the player who opened it discards it instead.
+func (m *Manager) Resign(gameID, playerID string) error {
+	game, err := m.GetGame(gameID)
+	if err != nil {
+		return err
 	}
 
-	// Check if reconnect window is still open (30 seconds)
-	if player.DisconnectedAt != nil {
-		elapsed := time.Since(*player.DisconnectedAt)
-		if elapsed > 30*time.Second {
-			log.Printf("Reconnect window expired for player %s (elapsed: %v)", player.Username, elapsed)
-			return nil, nil, errors.New("reconnect window expired (>30 seconds)")
+	if snap := game.Snapshot(); snap.Status == StatusWaiting {
+		if snap.Player1.ID != playerID {
+			return ErrInvalidPlayer
 		}
+		m.removeGame(gameID)
+		log.Printf("Player %s discarded waiting game %s", snap.Player1.Username, gameID)
+		return nil
 	}
+	return game.Resign(playerID)
+}
 
-	// Reconnect player
-	player.Connected = true
-	player.LastHeartbeat = time.Now()
-	player.DisconnectedAt = nil
+// ActiveGames returns the games in memory that username is waiting for or
+// playing
+func (m *Manager) ActiveGames(username string) []*Snapshot {
+	var active []*Snapshot
+	for _, game := range m.allGames() {
+		snap := game.Snapshot()
+		if snap.IsOver() {
+			continue
+		}
+		if (snap.Player1 != nil && snap.Player1.Username == username) ||
+			(snap.Player2 != nil && snap.Player2.Username == username) {
+			active = append(active, snap)
+		}
+	}
+	return active
+}
 
-	log.Printf("Player %s reconnected to game %s (was disconnected for %v)",
-		player.Username, gameID,
-		func() time.Duration {
-			if player.DisconnectedAt != nil {
-				return time.Since(*player.DisconnectedAt)
+// ActivePlayer returns the matchmaking game username is waiting for or
+// playing, and their player in it. Games opened through the REST game API
+// were not paired by the matchmaker and do not count.
+func (m *Manager) ActivePlayer(username string) (*Game, *Player, error) {
+	for _, game := range m.allGames() {
+		snap := game.Snapshot()
+		if snap.IsOver() || snap.Queue == "" {
+			continue
+		}
+		for _, player := range []*Player{snap.Player1, snap.Player2} {
+			if player != nil && !player.Hosted && player.Username == username {
+				return game, player, nil
 			}
-			return 0
-		}())
+		}
+	}
+	return nil, nil, ErrNotInGame
+}
 
-	return game, player, nil
+// GetGameBySession returns the game and player a session token belongs to,
+// without changing the player's connection state
+func (m *Manager) GetGameBySession(sessionToken string) (*Game, *Player, error) {
+	m.mu.RLock()
+	game, exists := m.games[m.sessionGames[sessionToken]]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, nil, ErrSessionNotFound
+	}
+
+	snap := game.Snapshot()
+	for _, p := range []*Player{snap.Player1, snap.Player2} {
+		if p != nil && p.SessionToken == sessionToken {
+			return game, p, nil
+		}
+	}
+	return nil, nil, ErrSessionNotFound
 }
 
 // GetGame retrieves a game by ID
@@ -181,6 +405,10 @@ func (m *Manager) GetGameByPlayer(playerID string) (*Game, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	return m.gameByPlayerLocked(playerID)
+}
+
+func (m *Manager) gameByPlayerLocked(playerID string) (*Game, error) {
 	gameID, exists := m.playerGames[playerID]
 	if !exists {
 		return nil, ErrGameNotFound
@@ -194,6 +422,18 @@ func (m *Manager) GetGameByPlayer(playerID string) (*Game, error) {
 	return game, nil
 }
 
+// allGames returns the games currently held in memory
+func (m *Manager) allGames() []*Game {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	games := make([]*Game, 0, len(m.games))
+	for _, game := range m.games {
+		games = append(games, game)
+	}
+	return games
+}
+
 // MakeMove processes a move in a game
 func (m *Manager) MakeMove(gameID, playerID string, column int) (int, error) {
 	game, err := m.GetGame(gameID)
@@ -207,12 +447,7 @@ func (m *Manager) MakeMove(gameID, playerID string, column int) (int, error) {
 	}
 
 	// Emit move event
-	m.emitMoveEvent(game, playerID, column, row)
-
-	// Check if game is finished
-	if game.Status == StatusFinished {
-		m.handleGameFinished(game)
-	}
+	m.emitMoveEvent(game.Snapshot(), playerID, column, row)
 
 	return row, nil
 }
@@ -224,26 +459,74 @@ func (m *Manager) HandleBotMove(gameID string) error {
 		return err
 	}
 
-	if game.Player2 == nil || !game.Player2.IsBot {
-		return errors.New("game does not have a bot")
+	snap := game.Snapshot()
+	if snap.Player2 == nil || !snap.Player2.Hosted {
+		return ErrNoBot
 	}
 
-	if game.CurrentTurn != Player2 {
-		return errors.New("not bot's turn")
+	if snap.CurrentTurn != Player2 {
+		return ErrNotBotTurn
 	}
 
-	// Add small delay to make it more natural
-	time.Sleep(500 * time.Millisecond)
+	// Add small delay to make it more natural. An engine's delay comes out of
+	// its turn, so it never takes more than half the time left.
+	engine := m.engine(snap.Player2.Engine)
+	delay := m.rules.BotMoveDelay
+	if engine != nil {
+		delay = min(delay, turnTimeLeft(snap, m.clock.Now())/2)
+	}
+	<-m.clock.After(delay)
+
+	if engine != nil {
+		err := m.makeEngineMove(engine, game, snap.Player2.ID)
+		if err == nil || !errors.Is(err, errEngineMove) {
+			return err
+		}
+		log.Printf("Engine %s failed in game %s, using the built-in bot: %v", engine.Name(), gameID, err)
+	}
 
 	column := game.GetBotMove()
 	if column == -1 {
-		return errors.New("bot could not find valid move")
+		return ErrNoBotMove
+	}
+
+	_, err = m.MakeMove(gameID, snap.Player2.ID, column)
+	return err
+}
+
+// errEngineMove marks engine failures that the built-in bot can make up for
+var errEngineMove = errors.New("engine move failed")
+
+// makeEngineMove asks engine for the hosted player's move and plays it. A
+// turn with no time left is not put to the engine, whose failures would
+// count against it; the turn timer skips the turn instead.
+func (m *Manager) makeEngineMove(engine Engine, game *Game, playerID string) error {
+	snap := game.Snapshot()
+	timeLeft := turnTimeLeft(snap, m.clock.Now())
+	if timeLeft <= 0 {
+		return ErrTurnOver
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeLeft)
+	defer cancel()
+
+	column, err := engine.BestMove(ctx, snap, timeLeft)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errEngineMove, err)
 	}
 
-	_, err = m.MakeMove(gameID, game.Player2.ID, column)
+	_, err = m.MakeMove(game.ID, playerID, column)
+	if errors.Is(err, ErrInvalidMove) || errors.Is(err, ErrColumnFull) {
+		return fmt.Errorf("%w: illegal column %d: %v", errEngineMove, column, err)
+	}
 	return err
 }
 
+// turnTimeLeft returns how long the player to move has left at now
+func turnTimeLeft(snap *Snapshot, now time.Time) time.Duration {
+	return snap.TurnStartedAt.Add(time.Duration(snap.TurnTimeoutSec) * time.Second).Sub(now)
+}
+
 // UpdatePlayerHeartbeat updates player's last heartbeat
 func (m *Manager) UpdatePlayerHeartbeat(playerID string) error {
 	game, err := m.GetGameByPlayer(playerID)
@@ -280,102 +563,46 @@ func (m *Manager) ReconnectPlayerByID(playerID string) (*Game, error) {
 }
 
 // cleanupDisconnectedGames checks for abandoned games
-func (m *Manager) cleanupDisconnectedGames() {
-	ticker := time.NewTicker(5 * time.Second)
+func (m *Manager) cleanupDisconnectedGames(ctx context.Context) {
+	ticker := m.clock.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		m.mu.Lock()
-		now := time.Now()
-
-		for gameID, game := range m.games {
-			if game.Status != StatusInProgress {
-				continue
-			}
-
-			// Check player1
-			if !game.Player1.IsBot && game.Player1.Connected {
-				if now.Sub(game.Player1.LastHeartbeat) > 30*time.Second {
-					log.Printf("Player %s timed out in game %s", game.Player1.Username, gameID)
-					game.AbandonGame(game.Player1.ID)
-					m.handleGameFinished(game)
-				}
-			}
-
-			// Check player2
-			if game.Player2 != nil && !game.Player2.IsBot && game.Player2.Connected {
-				if now.Sub(game.Player2.LastHeartbeat) > 30*time.Second {
-					log.Printf("Player %s timed out in game %s", game.Player2.Username, gameID)
-					game.AbandonGame(game.Player2.ID)
-					m.handleGameFinished(game)
-				}
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
 		}
 
-		m.mu.Unlock()
+		now := m.clock.Now()
+		for _, game := range m.allGames() {
+			game.ExpireHeartbeats(now, m.rules.HeartbeatTimeout)
+			game.ExpireDisconnected(now, m.rules.ReconnectWindow)
+		}
 	}
 }
 
-// monitorTurnTimers checks for turn timeouts (30s per turn, 60s inactivity forfeits)
-func (m *Manager) monitorTurnTimers() {
-	ticker := time.NewTicker(2 * time.Second) // Check every 2 seconds for responsiveness
+// monitorTurnTimers checks for turn timeouts and inactivity forfeits
+func (m *Manager) monitorTurnTimers(ctx context.Context) {
+	ticker := m.clock.NewTicker(2 * time.Second) // Check every 2 seconds for responsiveness
 	defer ticker.Stop()
 
-	for range ticker.C {
-		m.mu.RLock()
-		now := time.Now()
-		gamesToUpdate := make(map[string]*Game)
-
-		for gameID, game := range m.games {
-			if game.Status != StatusInProgress {
-				continue
-			}
-
-			// Check for turn timeout (30 seconds)
-			turnElapsed := now.Sub(game.TurnStartedAt)
-			if turnElapsed > time.Duration(game.TurnTimeoutSec)*time.Second {
-				// Skip turn if no move in 30 seconds
-				gamesToUpdate[gameID] = game
-				log.Printf("Turn timeout in game %s, skipping turn (elapsed: %v)", gameID, turnElapsed)
-			}
-
-			// Check for total inactivity timeout (60 seconds = forfeit)
-			inactivityElapsed := now.Sub(game.LastMoveAt)
-			if inactivityElapsed > 60*time.Second {
-				// Forfeit game due to inactivity
-				var inactivePlayerID string
-				if game.CurrentTurn == Player1 {
-					inactivePlayerID = game.Player1.ID
-				} else if game.Player2 != nil {
-					inactivePlayerID = game.Player2.ID
-				}
-
-				if inactivePlayerID != "" {
-					log.Printf("Inactivity timeout in game %s, forfeiting game (elapsed: %v)", gameID, inactivityElapsed)
-					// Will handle forfeit after releasing read lock
-					game.AbandonGame(inactivePlayerID)
-					m.handleGameFinished(game)
-				}
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
 		}
 
-		m.mu.RUnlock()
-
-		// Skip turns outside of read lock to allow game updates
-		for gameID, game := range gamesToUpdate {
-			game.SkipTurn()
-			log.Printf("Turn skipped for game %s", gameID)
-
-			// Trigger game update callback to notify clients
-			if m.onGameUpdate != nil {
-				go m.onGameUpdate(gameID)
-			}
+		now := m.clock.Now()
+		for _, game := range m.allGames() {
+			game.CheckTurnTimer(now, m.rules.InactivityTimeout)
 		}
 	}
 }
 
 // handleGameFinished processes a finished game
-func (m *Manager) handleGameFinished(game *Game) {
+func (m *Manager) handleGameFinished(game *Snapshot) {
 	log.Printf("Game %s finished: %s", game.ID, game.Result)
 
 	// Save to database
@@ -386,43 +613,124 @@ func (m *Manager) handleGameFinished(game *Game) {
 	// Emit game finished event
 	m.emitGameFinishedEvent(game)
 
-	// Clean up after a delay
+	m.mu.RLock()
+	callbacks := m.onGameFinished
+	m.mu.RUnlock()
+	for _, callback := range callbacks {
+		callback(game)
+	}
+
+	// Clean up after a delay, or straight away on shutdown
+	m.mu.RLock()
+	done := m.done
+	m.mu.RUnlock()
+
 	go func() {
-		time.Sleep(1 * time.Minute)
+		select {
+		case <-m.clock.After(m.rules.FinishedGameRetention):
+		case <-done:
+		}
 		m.removeGame(game.ID)
 	}()
 }
 
+// InProgressCount returns the number of games currently being played
+func (m *Manager) InProgressCount() int {
+	count := 0
+	for _, game := range m.allGames() {
+		if game.Snapshot().Status == StatusInProgress {
+			count++
+		}
+	}
+	return count
+}
+
+// WaitForGames blocks until no game is in progress or ctx is done. Games
+// still running when ctx ends are abandoned without a winner. Either way it
+// returns once every finished game has been saved, reporting how many games
+// had to be abandoned.
+func (m *Manager) WaitForGames(ctx context.Context) int {
+	ticker := m.clock.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	abandoned := 0
+	for m.InProgressCount() > 0 {
+		select {
+		case <-ticker.C():
+			continue
+		case <-ctx.Done():
+		}
+
+		for _, game := range m.allGames() {
+			if game.Snapshot().Status == StatusInProgress {
+				// No player has this ID, so nobody is credited with a win
+				game.AbandonGame("")
+				abandoned++
+			}
+		}
+		break
+	}
+
+	// Waiting games have no watcher work left; drop them so their watchers exit
+	for _, game := range m.allGames() {
+		if game.Snapshot().Status == StatusWaiting {
+			m.removeGame(game.ID)
+		}
+	}
+
+	m.watchers.Wait()
+	return abandoned
+}
+
 // removeGame removes a game from memory
 func (m *Manager) removeGame(gameID string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	game, exists := m.games[gameID]
 	if !exists {
+		m.mu.Unlock()
 		return
 	}
 
+	snap := game.Snapshot()
+
 	// Clean up player mappings
-	delete(m.playerGames, game.Player1.ID)
-	if game.Player1.SessionToken != "" {
-		delete(m.sessionGames, game.Player1.SessionToken)
+	delete(m.playerGames, snap.Player1.ID)
+	if snap.Player1.SessionToken != "" {
+		delete(m.sessionGames, snap.Player1.SessionToken)
 	}
 
-	if game.Player2 != nil {
-		delete(m.playerGames, game.Player2.ID)
-		if game.Player2.SessionToken != "" {
-			delete(m.sessionGames, game.Player2.SessionToken)
+	if snap.Player2 != nil {
+		delete(m.playerGames, snap.Player2.ID)
+		if snap.Player2.SessionToken != "" {
+			delete(m.sessionGames, snap.Player2.SessionToken)
 		}
 	}
 
 	delete(m.games, gameID)
+	onGameRemoved := m.onGameRemoved
+	m.mu.Unlock()
+
+	game.Stop()
+	if onGameRemoved != nil {
+		onGameRemoved(gameID)
+	}
 
 	log.Printf("Game %s removed from memory (cleaned up session tokens)", gameID)
 }
 
+// ReconnectWindow returns how long a disconnected player may take to reconnect
+// before forfeiting
+func (m *Manager) ReconnectWindow() time.Duration {
+	return m.rules.ReconnectWindow
+}
+
 // saveGameToDB saves a completed game to the database
-func (m *Manager) saveGameToDB(game *Game) error {
+func (m *Manager) saveGameToDB(game *Snapshot) error {
+	// Without a database, as in tests, games are only kept in memory
+	if m.db == nil {
+		return nil
+	}
 	ctx := context.Background()
 
 	// Ensure players exist in database
@@ -453,13 +761,15 @@ func (m *Manager) saveGameToDB(game *Game) error {
 		Winner:     winner,
 		Result:     string(game.Result),
 		BoardState: game.Board.ToArray(),
+		Queue:      game.Queue,
+		Rated:      game.Rated,
 		StartedAt:  game.StartedAt,
 		FinishedAt: game.FinishedAt,
 	})
 }
 
 // Kafka event emission methods
-func (m *Manager) emitGameStartedEvent(game *Game) {
+func (m *Manager) emitGameStartedEvent(game *Snapshot) {
 	if m.kafkaProducer == nil {
 		return
 	}
@@ -472,31 +782,38 @@ func (m *Manager) emitGameStartedEvent(game *Game) {
 	m.emitGameStartedEventWithState(game, activeGames, totalPlayers)
 }
 
-func (m *Manager) emitGameStartedEventWithState(game *Game, activeGames, totalPlayers int) {
+func (m *Manager) emitGameStartedEventWithState(game *Snapshot, activeGames, totalPlayers int) {
 	if m.kafkaProducer == nil {
 		return
 	}
 
+	// Only games with both players start, but the event must not take the
+	// server down if that ever changes
+	player2, player2Bot := "", false
+	if game.Player2 != nil {
+		player2, player2Bot = game.Player2.Username, game.Player2.IsBot
+	}
+
 	event := map[string]interface{}{
 		"event_type":    "game_started",
 		"game_id":       game.ID,
 		"player1":       game.Player1.Username,
-		"player2":       game.Player2.Username,
-		"timestamp":     time.Now().Unix(),
-		"timestamp_iso": time.Now().Format(time.RFC3339),
-		"hour_of_day":   time.Now().Hour(),
-		"day_of_week":   time.Now().Weekday().String(),
+		"player2":       player2,
+		"timestamp":     m.clock.Now().Unix(),
+		"timestamp_iso": m.clock.Now().Format(time.RFC3339),
+		"hour_of_day":   m.clock.Now().Hour(),
+		"day_of_week":   m.clock.Now().Weekday().String(),
 		// Kafka metrics visible in UI
 		"active_games":  activeGames,
 		"total_players": totalPlayers,
-		"is_bot_game":   game.Player2.Username == "Bot",
+		"is_bot_game":   game.Player1.IsBot || player2Bot,
 	}
 
 	data, _ := json.Marshal(event)
 	m.kafkaProducer.SendMessage(context.Background(), "game-events", data)
 }
 
-func (m *Manager) emitMoveEvent(game *Game, playerID string, column, row int) {
+func (m *Manager) emitMoveEvent(game *Snapshot, playerID string, column, row int) {
 	if m.kafkaProducer == nil {
 		return
 	}
@@ -505,20 +822,14 @@ func (m *Manager) emitMoveEvent(game *Game, playerID string, column, row int) {
 	isBot := false
 	if game.Player1.ID == playerID {
 		username = game.Player1.Username
+		isBot = game.Player1.IsBot
 	} else if game.Player2 != nil {
 		username = game.Player2.Username
-		isBot = game.Player2.Username == "Bot"
+		isBot = game.Player2.IsBot
 	}
 
 	// Count total moves in game
-	totalMoves := 0
-	for _, col := range game.Board.Grid {
-		for _, cell := range col {
-			if cell != 0 {
-				totalMoves++
-			}
-		}
-	}
+	totalMoves := game.MoveCount()
 
 	event := map[string]interface{}{
 		"event_type":    "move_made",
@@ -526,9 +837,9 @@ func (m *Manager) emitMoveEvent(game *Game, playerID string, column, row int) {
 		"player":        username,
 		"column":        column,
 		"row":           row,
-		"timestamp":     time.Now().Unix(),
-		"timestamp_iso": time.Now().Format(time.RFC3339),
-		"hour_of_day":   time.Now().Hour(),
+		"timestamp":     m.clock.Now().Unix(),
+		"timestamp_iso": m.clock.Now().Format(time.RFC3339),
+		"hour_of_day":   m.clock.Now().Hour(),
 		// Kafka metrics
 		"move_number": totalMoves,
 		"is_bot_move": isBot,
@@ -538,7 +849,7 @@ func (m *Manager) emitMoveEvent(game *Game, playerID string, column, row int) {
 	m.kafkaProducer.SendMessage(context.Background(), "game-events", data)
 }
 
-func (m *Manager) emitGameFinishedEvent(game *Game) {
+func (m *Manager) emitGameFinishedEvent(game *Snapshot) {
 	if m.kafkaProducer == nil {
 		return
 	}
@@ -553,16 +864,15 @@ func (m *Manager) emitGameFinishedEvent(game *Game) {
 		winner = game.Winner.Username
 	}
 
-	// Count total moves in game
-	totalMoves := 0
-	for _, col := range game.Board.Grid {
-		for _, cell := range col {
-			if cell != 0 {
-				totalMoves++
-			}
-		}
+	// A game abandoned before anyone joined has no second player
+	player2, player2Bot := "", false
+	if game.Player2 != nil {
+		player2, player2Bot = game.Player2.Username, game.Player2.IsBot
 	}
 
+	// Count total moves in game
+	totalMoves := game.MoveCount()
+
 	m.mu.RLock()
 	activeGames := len(m.games)
 	totalPlayers := len(m.playerGames)
@@ -572,20 +882,20 @@ func (m *Manager) emitGameFinishedEvent(game *Game) {
 		"event_type":    "game_finished",
 		"game_id":       game.ID,
 		"player1":       game.Player1.Username,
-		"player2":       game.Player2.Username,
+		"player2":       player2,
 		"winner":        winner,
 		"result":        string(game.Result),
 		"duration":      duration,
-		"timestamp":     time.Now().Unix(),
-		"timestamp_iso": time.Now().Format(time.RFC3339),
-		"hour_of_day":   time.Now().Hour(),
-		"day_of_week":   time.Now().Weekday().String(),
+		"timestamp":     m.clock.Now().Unix(),
+		"timestamp_iso": m.clock.Now().Format(time.RFC3339),
+		"hour_of_day":   m.clock.Now().Hour(),
+		"day_of_week":   m.clock.Now().Weekday().String(),
 		// Kafka metrics visible in UI
 		"total_moves":       totalMoves,
 		"active_games":      activeGames,
 		"total_players":     totalPlayers,
 		"game_duration_sec": int(duration),
-		"was_bot_game":      game.Player2.Username == "Bot",
+		"was_bot_game":      game.Player1.IsBot || player2Bot,
 	}
 
 	data, _ := json.Marshal(event)
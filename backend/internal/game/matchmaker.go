@@ -1,126 +1,556 @@
 package game
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/yourusername/4-in-a-row/internal/clock"
 )
 
-const (
-	MatchmakingTimeout = 10 * time.Second
-)
+// BotUsername is the name the built-in bot plays under
+const BotUsername = "Bot"
 
 type MatchRequest struct {
 	Player    *Player
 	CreatedAt time.Time
 }
 
+// pendingMatch is a pairing made by AddPlayer whose joining player has not
+// taken their seat yet, see JoinMatch
+type pendingMatch struct {
+	player  *Player
+	queue   *queue
+	waiting *MatchRequest
+}
+
+// Matchmaker pairs players by rating within named queues, such as blitz or
+// casual, each with its own time control and bot fallback. Each player
+// accepts opponents within a rating window that starts at
+// Rules.MatchRatingWindow and widens the longer they wait; players still
+// unmatched after their queue's matchmaking timeout play a bot.
 type Matchmaker struct {
-	queue       []*MatchRequest
-	mu          sync.Mutex
-	gameManager *Manager
+	queues          []*queue // in configuration order; the first is the default
+	mu              sync.Mutex
+	gameManager     *Manager
+	window          int // see Rules.MatchRatingWindow
+	windowMax       int
+	widenEvery      time.Duration
+	rematchCooldown time.Duration
+	clock           clock.Clock
+	draining        bool
+	botEngine       string                  // engine for the fallback bot, "" for the built-in Bot
+	recent          map[[2]string]time.Time // username pair -> when they were last paired
+	pending         map[string]pendingMatch // joining player ID -> their pairing, until JoinMatch
+	onMatch         func(player *Player, game *Game)
+	statusEvery     time.Duration // see Rules.QueueStatusInterval
+	lastStatus      time.Time
+	onStatus        func(status QueueStatus)
 }
 
-func NewMatchmaker(gameManager *Manager) *Matchmaker {
+// NewMatchmaker creates a matchmaker that follows the matchmaking rules in
+// rules. Without rules.Queues it runs a single DefaultQueue using the
+// general timeouts. It shares the game manager's clock.
+func NewMatchmaker(gameManager *Manager, rules Rules) *Matchmaker {
+	queueRules := rules.Queues
+	if len(queueRules) == 0 {
+		queueRules = []QueueRules{{
+			Name:               DefaultQueue,
+			TurnTimeout:        rules.TurnTimeout,
+			MatchmakingTimeout: rules.MatchmakingTimeout,
+			BotFallback:        BotFallbackEngine,
+			Rated:              true,
+		}}
+	}
+	queues := make([]*queue, len(queueRules))
+	for i, qr := range queueRules {
+		queues[i] = &queue{rules: qr}
+	}
+
 	return &Matchmaker{
-		queue:       make([]*MatchRequest, 0),
-		gameManager: gameManager,
+		queues:          queues,
+		gameManager:     gameManager,
+		window:          rules.MatchRatingWindow,
+		windowMax:       rules.MatchRatingWindowMax,
+		widenEvery:      rules.MatchWindowWidenEvery,
+		rematchCooldown: rules.RematchCooldown,
+		statusEvery:     rules.QueueStatusInterval,
+		clock:           gameManager.clock,
+		recent:          make(map[[2]string]time.Time),
+		pending:         make(map[string]pendingMatch),
 	}
 }
 
-// Run starts the matchmaker loop
-func (mm *Matchmaker) Run() {
-	ticker := time.NewTicker(1 * time.Second)
+// SetBotEngine makes players who time out in queues with the engine
+// fallback face the engine registered with the manager as name instead of
+// the built-in Bot
+func (mm *Matchmaker) SetBotEngine(name string) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.botEngine = name
+}
+
+// SetMatchCallback sets a callback for when the matchmaker moves a waiting
+// player out of the game created for them and into another waiting
+// player's game, or into a fresh game of their own if that fails, so that
+// the player's clients can follow. It is called after the player has
+// joined game.
+func (mm *Matchmaker) SetMatchCallback(callback func(player *Player, game *Game)) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.onMatch = callback
+}
+
+// SetQueueStatusCallback sets a callback that receives the status of every
+// waiting player each Rules.QueueStatusInterval. It is called without the
+// matchmaker's lock held.
+func (mm *Matchmaker) SetQueueStatusCallback(callback func(status QueueStatus)) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.onStatus = callback
+}
+
+// Run starts the matchmaker loop; it returns when ctx is cancelled
+func (mm *Matchmaker) Run(ctx context.Context) {
+	ticker := mm.clock.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		mm.processQueue()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			mm.processQueue()
+			mm.publishQueueStatus()
+		}
 	}
 }
 
-// AddPlayer adds a player to the matchmaking queue
-func (mm *Matchmaker) AddPlayer(username string) (*Player, *Game, bool) {
+// AddPlayer adds a player, human or bot account, to the named matchmaking
+// queue, or the default queue if queueName is empty. A username is only
+// matched once at a time: it fails with ErrAlreadyPlaying while the
+// username is still waiting or playing a matchmaking game, see
+// Manager.ActivePlayer, or has been paired and is yet to JoinMatch. It also
+// fails with ErrUnknownQueue for a queue that is not configured and with
+// ErrServerDraining once Drain has been called.
+func (mm *Matchmaker) AddPlayer(player *Player, queueName string) (*Game, bool, error) {
 	mm.mu.Lock()
 	defer mm.mu.Unlock()
 
-	player := &Player{
-		ID:            uuid.New().String(),
-		Username:      username,
-		SessionToken:  uuid.New().String(),
-		IsBot:         false,
-		Connected:     true,
-		LastHeartbeat: time.Now(),
+	if mm.draining {
+		return nil, false, ErrServerDraining
+	}
+	q := mm.queueLocked(queueName)
+	if q == nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrUnknownQueue, queueName)
+	}
+	if _, _, err := mm.gameManager.ActivePlayer(player.Username); err == nil || mm.joiningLocked(player.Username) {
+		return nil, false, fmt.Errorf("%w: %s", ErrAlreadyPlaying, player.Username)
 	}
 
-	// Check if there's already someone waiting
-	if len(mm.queue) > 0 {
-		waitingRequest := mm.queue[0]
-		mm.queue = mm.queue[1:]
+	request := &MatchRequest{
+		Player:    player,
+		CreatedAt: mm.clock.Now(),
+	}
+
+	// Check if someone suitable is already waiting
+	for i := mm.bestMatchLocked(q, request); i >= 0; i = mm.bestMatchLocked(q, request) {
+		waitingRequest := q.requests[i]
+		q.remove(i)
 
-		// Retrieve the existing game that was created when the first player joined.
-		// The initial AddPlayer call creates a game for the waiting player, so reuse it
-		// to avoid creating duplicate games and mismatched game IDs for clients.
+		// Reuse the game created when the waiting player joined, which their
+		// client already knows. A player whose game is gone is stale.
 		game, err := mm.gameManager.GetGameByPlayer(waitingRequest.Player.ID)
-		if err != nil {
-			// Fallback: if for some reason the game isn't found, create a new one.
-			game = mm.gameManager.CreateGame(waitingRequest.Player)
+		if err != nil || game.Snapshot().Status != StatusWaiting {
+			log.Printf("Dropped %s from matchmaking queue %s: no game waiting", waitingRequest.Player.Username, q.rules.Name)
+			continue
 		}
+		q.recordWait(request.CreatedAt.Sub(waitingRequest.CreatedAt))
+		mm.recordPairLocked(waitingRequest.Player, player, request.CreatedAt)
+		mm.pending[player.ID] = pendingMatch{player: player, queue: q, waiting: waitingRequest}
 
 		// We return a matched=true so the caller (API layer) can perform
-		// JoinGame after the WS client has set its playerID/gameID. This
+		// JoinMatch after the WS client has set its playerID/gameID. This
 		// prevents a race where the game update callback fires before the
 		// client's fields are assigned and the client misses the update.
-		log.Printf("Matched players (deferred Join): %s vs %s", waitingRequest.Player.Username, player.Username)
+		log.Printf("Matched players in %s (deferred Join): %s (%.0f) vs %s (%.0f)", q.rules.Name,
+			waitingRequest.Player.Username, waitingRequest.Player.Rating, player.Username, player.Rating)
 
-		return player, game, true
+		return game, true, nil
 	}
 
-	// No one waiting, add to queue
-	request := &MatchRequest{
-		Player:    player,
-		CreatedAt: time.Now(),
-	}
-	mm.queue = append(mm.queue, request)
+	// No one suitable waiting, add to queue
+	q.requests = append(q.requests, request)
 
 	// Create game immediately for this player
-	game := mm.gameManager.CreateGame(player)
+	game := mm.gameManager.CreateGameWithOptions(player, q.gameOptions())
 
-	log.Printf("Player %s added to matchmaking queue", username)
+	log.Printf("Player %s (%.0f) added to matchmaking queue %s", player.Username, player.Rating, q.rules.Name)
 
-	return player, game, false
+	return game, false, nil
 }
 
-// processQueue checks for timeout and matches with bot
+// JoinMatch seats player in game, the game AddPlayer paired them into. If
+// the seat cannot be taken, the player who was waiting in it goes back to
+// their place in the queue, to be paired again or handed to the bot
+// fallback in time.
+func (mm *Matchmaker) JoinMatch(game *Game, player *Player) error {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	match, ok := mm.pending[player.ID]
+	delete(mm.pending, player.ID)
+
+	err := mm.gameManager.JoinGame(game.ID, player)
+	if err != nil && ok && mm.waitingLocked(match.waiting) && slices.Contains(mm.queues, match.queue) {
+		match.queue.insert(match.waiting)
+		log.Printf("Player %s back in matchmaking queue %s after a failed join: %v",
+			match.waiting.Player.Username, match.queue.rules.Name, err)
+	}
+	return err
+}
+
+// joiningLocked reports whether username has been paired by AddPlayer and
+// is yet to take their seat. The caller must hold mm.mu.
+func (mm *Matchmaker) joiningLocked(username string) bool {
+	for _, match := range mm.pending {
+		if match.player.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// QueueStatus returns where a waiting player stands in their queue; ok is
+// false if the player is not waiting
+func (mm *Matchmaker) QueueStatus(playerID string) (status QueueStatus, ok bool) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	now := mm.clock.Now()
+	for _, q := range mm.queues {
+		for i, request := range q.requests {
+			if request.Player.ID == playerID {
+				return q.status(i, now), true
+			}
+		}
+	}
+	return QueueStatus{}, false
+}
+
+// publishQueueStatus hands the status of every waiting player to the status
+// callback, if one is set and a status interval has passed
+func (mm *Matchmaker) publishQueueStatus() {
+	mm.mu.Lock()
+	now := mm.clock.Now()
+	onStatus := mm.onStatus
+	if onStatus == nil || mm.statusEvery <= 0 || now.Sub(mm.lastStatus) < mm.statusEvery {
+		mm.mu.Unlock()
+		return
+	}
+	mm.lastStatus = now
+
+	var statuses []QueueStatus
+	for _, q := range mm.queues {
+		for i := range q.requests {
+			statuses = append(statuses, q.status(i, now))
+		}
+	}
+	mm.mu.Unlock()
+
+	for _, status := range statuses {
+		onStatus(status)
+	}
+}
+
+// QueueStats describes every queue, in configuration order
+func (mm *Matchmaker) QueueStats() []QueueStats {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	stats := make([]QueueStats, len(mm.queues))
+	for i, q := range mm.queues {
+		stats[i] = q.stats()
+	}
+	return stats
+}
+
+// queueLocked returns the queue called name, the default queue if name is
+// empty, or nil if there is no such queue. The caller must hold mm.mu.
+func (mm *Matchmaker) queueLocked(name string) *queue {
+	if name == "" {
+		return mm.queues[0]
+	}
+	for _, q := range mm.queues {
+		if q.rules.Name == name {
+			return q
+		}
+	}
+	return nil
+}
+
+// AddQueue opens another queue, such as one for an event that only runs
+// for a while. A zero TurnTimeout or MatchmakingTimeout in rules means the
+// general one. It fails with ErrQueueExists if a queue is already called
+// rules.Name.
+func (mm *Matchmaker) AddQueue(rules QueueRules) error {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if rules.Name == "" || mm.queueLocked(rules.Name) != nil {
+		return fmt.Errorf("%w: %q", ErrQueueExists, rules.Name)
+	}
+	if rules.TurnTimeout == 0 {
+		rules.TurnTimeout = mm.gameManager.rules.TurnTimeout
+	}
+	if rules.MatchmakingTimeout == 0 {
+		rules.MatchmakingTimeout = mm.gameManager.rules.MatchmakingTimeout
+	}
+	mm.queues = append(mm.queues, &queue{rules: rules})
+
+	log.Printf("Matchmaking queue %s opened", rules.Name)
+	return nil
+}
+
+// RemoveQueue closes a queue opened with AddQueue and discards the games
+// created for the players still waiting in it, whom it returns so that
+// their clients can be told. Games already paired in the queue play on.
+// The default queue cannot be removed.
+func (mm *Matchmaker) RemoveQueue(name string) []*Player {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	for i, q := range mm.queues {
+		if i == 0 || q.rules.Name != name {
+			continue
+		}
+		mm.queues = append(mm.queues[:i], mm.queues[i+1:]...)
+
+		waiting := make([]*Player, 0, len(q.requests))
+		for _, request := range q.requests {
+			if game, err := mm.gameManager.GetGameByPlayer(request.Player.ID); err == nil && game.Snapshot().Status == StatusWaiting {
+				mm.gameManager.removeGame(game.ID)
+			}
+			waiting = append(waiting, request.Player)
+		}
+
+		log.Printf("Matchmaking queue %s closed with %d players waiting", name, len(waiting))
+		return waiting
+	}
+	return nil
+}
+
+// Drain stops the matchmaker from accepting players and empties the queues,
+// discarding the games created for the players who were still waiting. It
+// returns those players.
+func (mm *Matchmaker) Drain() []*Player {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	mm.draining = true
+	var waiting []*Player
+	for _, q := range mm.queues {
+		for _, request := range q.requests {
+			if game, err := mm.gameManager.GetGameByPlayer(request.Player.ID); err == nil {
+				mm.gameManager.removeGame(game.ID)
+			}
+			waiting = append(waiting, request.Player)
+		}
+		q.requests = nil
+	}
+
+	log.Printf("Matchmaker draining: no longer accepting players, dropped %d waiting", len(waiting))
+	return waiting
+}
+
+// processQueue pairs waiting players whose rating windows have widened
+// enough to meet, then applies each queue's bot fallback to those who have
+// waited too long
 func (mm *Matchmaker) processQueue() {
 	mm.mu.Lock()
 	defer mm.mu.Unlock()
 
-	now := time.Now()
+	now := mm.clock.Now()
+	for pair, at := range mm.recent {
+		if now.Sub(at) >= mm.rematchCooldown {
+			delete(mm.recent, pair)
+		}
+	}
+
+	for _, q := range mm.queues {
+		mm.processQueueLocked(q, now)
+	}
+}
+
+// processQueueLocked does processQueue's work for one queue. The caller
+// must hold mm.mu.
+func (mm *Matchmaker) processQueueLocked(q *queue, now time.Time) {
+	// The queue is in arrival order, so the longest waiting players get
+	// first pick and host the game
+	for i := 0; i < len(q.requests); i++ {
+		j := mm.bestMatchLocked(q, q.requests[i])
+		if j < 0 {
+			continue
+		}
+		if j < i {
+			i, j = j, i
+		}
+		host, guest := q.requests[i], q.requests[j]
+
+		if err := mm.pairWaitingLocked(q, host, guest, now); err != nil {
+			// Whoever still has a waiting game stays in the queue to be
+			// paired later; a request whose game has gone is dropped
+			log.Printf("Error pairing %s with %s: %v", host.Player.Username, guest.Player.Username, err)
+			if !mm.waitingLocked(guest) {
+				q.remove(j)
+			}
+			if !mm.waitingLocked(host) {
+				q.remove(i)
+				i--
+			}
+			continue
+		}
+		q.remove(j)
+		q.remove(i)
+		i--
+		q.recordWait(now.Sub(host.CreatedAt))
+		q.recordWait(now.Sub(guest.CreatedAt))
+	}
+
+	if q.rules.BotFallback == BotFallbackNone {
+		return
+	}
+
 	remainingQueue := make([]*MatchRequest, 0)
 
-	for _, request := range mm.queue {
-		if now.Sub(request.CreatedAt) >= MatchmakingTimeout {
+	for _, request := range q.requests {
+		if wait := now.Sub(request.CreatedAt); wait >= q.rules.MatchmakingTimeout {
 			// Timeout - match with bot
-			mm.matchWithBot(request.Player)
-			log.Printf("Player %s matched with bot after timeout", request.Player.Username)
+			mm.matchWithBot(request.Player, q.rules.BotFallback)
+			q.recordWait(wait)
+			log.Printf("Player %s matched with bot in %s after timeout", request.Player.Username, q.rules.Name)
 		} else {
 			remainingQueue = append(remainingQueue, request)
 		}
 	}
 
-	mm.queue = remainingQueue
+	q.requests = remainingQueue
+}
+
+// bestMatchLocked returns the index of the request in q that suits
+// request best, the closest in rating, or -1 if none is acceptable. The
+// caller must hold mm.mu.
+func (mm *Matchmaker) bestMatchLocked(q *queue, request *MatchRequest) int {
+	now := mm.clock.Now()
+	best, bestGap := -1, 0.0
+	for i, candidate := range q.requests {
+		if candidate.Player.Username == request.Player.Username || !mm.acceptableLocked(request, candidate, now) {
+			continue
+		}
+		if gap := math.Abs(request.Player.Rating - candidate.Player.Rating); best < 0 || gap < bestGap {
+			best, bestGap = i, gap
+		}
+	}
+	return best
+}
+
+// acceptableLocked reports whether two requests may be paired: their
+// ratings must lie within the wider of their windows, and they must not
+// have been paired with each other recently. The caller must hold mm.mu.
+func (mm *Matchmaker) acceptableLocked(a, b *MatchRequest, now time.Time) bool {
+	if at, ok := mm.recent[pairKey(a.Player.Username, b.Player.Username)]; ok && now.Sub(at) < mm.rematchCooldown {
+		return false
+	}
+	window := math.Max(mm.ratingWindow(a, now), mm.ratingWindow(b, now))
+	return math.Abs(a.Player.Rating-b.Player.Rating) <= window
+}
+
+// ratingWindow returns how far from its own rating request accepts an
+// opponent after waiting until now
+func (mm *Matchmaker) ratingWindow(request *MatchRequest, now time.Time) float64 {
+	window := mm.window
+	if mm.widenEvery > 0 {
+		window += mm.window * int(now.Sub(request.CreatedAt)/mm.widenEvery)
+	}
+	if window > mm.windowMax {
+		window = mm.windowMax
+	}
+	return float64(window)
+}
+
+// recordPairLocked remembers that two players were paired so they are not
+// paired again straight away. The caller must hold mm.mu.
+func (mm *Matchmaker) recordPairLocked(a, b *Player, now time.Time) {
+	if mm.rematchCooldown > 0 {
+		mm.recent[pairKey(a.Username, b.Username)] = now
+	}
+}
+
+// pairKey identifies a pair of usernames regardless of order
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// waitingLocked reports whether request's player still has a game waiting
+// for an opponent. The caller must hold mm.mu.
+func (mm *Matchmaker) waitingLocked(request *MatchRequest) bool {
+	game, err := mm.gameManager.GetGameByPlayer(request.Player.ID)
+	return err == nil && game.Snapshot().Status == StatusWaiting
+}
+
+// pairWaitingLocked matches two players who are both waiting in q: guest
+// leaves the game created for it and joins host's. If guest cannot join, it
+// is given a new game of its own to keep waiting in. The caller must hold
+// mm.mu.
+func (mm *Matchmaker) pairWaitingLocked(q *queue, host, guest *MatchRequest, now time.Time) error {
+	hostGame, err := mm.gameManager.GetGameByPlayer(host.Player.ID)
+	if err != nil {
+		return err
+	}
+	guestGame, err := mm.gameManager.GetGameByPlayer(guest.Player.ID)
+	if err != nil {
+		return err
+	}
+	if hostGame.Snapshot().Status != StatusWaiting || guestGame.Snapshot().Status != StatusWaiting {
+		return ErrGameFull
+	}
+
+	mm.gameManager.removeGame(guestGame.ID)
+	if err := mm.gameManager.JoinGame(hostGame.ID, guest.Player); err != nil {
+		game := mm.gameManager.CreateGameWithOptions(guest.Player, q.gameOptions())
+		if mm.onMatch != nil {
+			mm.onMatch(guest.Player, game)
+		}
+		return err
+	}
+	mm.recordPairLocked(host.Player, guest.Player, now)
+
+	if mm.onMatch != nil {
+		mm.onMatch(guest.Player, hostGame)
+	}
+
+	log.Printf("Matched waiting players: %s (%.0f) vs %s (%.0f) after %v",
+		host.Player.Username, host.Player.Rating, guest.Player.Username, guest.Player.Rating, now.Sub(host.CreatedAt))
+	return nil
 }
 
-// matchWithBot creates a bot opponent for a player
-func (mm *Matchmaker) matchWithBot(player *Player) {
-	bot := &Player{
-		ID:            uuid.New().String(),
-		Username:      "Bot",
-		IsBot:         true,
-		Connected:     true,
-		LastHeartbeat: time.Now(),
+// matchWithBot creates a bot opponent for a player following fallback. The
+// built-in Bot plays at a strength suited to the player's rating.
+func (mm *Matchmaker) matchWithBot(player *Player, fallback BotFallback) {
+	engine := ""
+	if fallback == BotFallbackEngine {
+		engine = mm.botEngine
+	}
+	bot := mm.gameManager.NewHostedBot(engine)
+	if bot.Engine == "" {
+		bot.Difficulty = DifficultyFor(player.Rating)
 	}
 
 	// Find the player's game
@@ -136,16 +566,25 @@ func (mm *Matchmaker) matchWithBot(player *Player) {
 	log.Printf("Bot joined game %s with player %s", game.ID, player.Username)
 }
 
-// RemovePlayer removes a player from the queue (if disconnected before match)
-func (mm *Matchmaker) RemovePlayer(playerID string) {
+// RemovePlayer takes a waiting player out of their queue and discards the
+// game created for them. It reports whether the player was waiting; a
+// player who has already been matched keeps their game.
+func (mm *Matchmaker) RemovePlayer(playerID string) bool {
 	mm.mu.Lock()
 	defer mm.mu.Unlock()
 
-	for i, request := range mm.queue {
-		if request.Player.ID == playerID {
-			mm.queue = append(mm.queue[:i], mm.queue[i+1:]...)
-			log.Printf("Player %s removed from matchmaking queue", request.Player.Username)
-			return
+	for _, q := range mm.queues {
+		for i, request := range q.requests {
+			if request.Player.ID != playerID {
+				continue
+			}
+			q.remove(i)
+			if game, err := mm.gameManager.GetGameByPlayer(playerID); err == nil && game.Snapshot().Status == StatusWaiting {
+				mm.gameManager.removeGame(game.ID)
+			}
+			log.Printf("Player %s removed from matchmaking queue %s", request.Player.Username, q.rules.Name)
+			return true
 		}
 	}
+	return false
 }
@@ -8,8 +8,9 @@ import (
 	"time"
 )
 
-// StartMetricsEmitter starts a background goroutine that emits periodic system metrics to Kafka
-func (m *Manager) StartMetricsEmitter() {
+// StartMetricsEmitter starts a background goroutine that emits periodic system
+// metrics to Kafka until ctx is cancelled
+func (m *Manager) StartMetricsEmitter(ctx context.Context) {
 	if m.kafkaProducer == nil {
 		log.Println("Kafka producer not available, metrics emitter disabled")
 		return
@@ -18,21 +19,25 @@ func (m *Manager) StartMetricsEmitter() {
 	log.Println("Starting metrics emitter (sends stats every 60 seconds)")
 
 	// Emit metrics every minute
-	ticker := time.NewTicker(60 * time.Second)
+	ticker := m.clock.NewTicker(60 * time.Second)
 	go func() {
+		defer ticker.Stop()
+
 		// Send initial metrics immediately
 		m.emitSystemMetrics()
 
-		for range ticker.C {
-			m.emitSystemMetrics()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				m.emitSystemMetrics()
+			}
 		}
 	}()
 }
 
 func (m *Manager) emitSystemMetrics() {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	// Calculate metrics
 	activeGamesCount := 0
 	inProgressGames := 0
@@ -40,10 +45,14 @@ func (m *Manager) emitSystemMetrics() {
 	finishedGames := 0
 	botGames := 0
 	humanGames := 0
+	connectedPlayers := 0
+	disconnectedPlayers := 0
+
+	for _, game := range m.allGames() {
+		snap := game.Snapshot()
 
-	for _, game := range m.games {
 		activeGamesCount++
-		switch game.Status {
+		switch snap.Status {
 		case StatusWaiting:
 			waitingGames++
 		case StatusInProgress:
@@ -52,23 +61,17 @@ func (m *Manager) emitSystemMetrics() {
 			finishedGames++
 		}
 
-		if game.Player2 != nil && game.Player2.Username == "Bot" {
+		if snap.Player2 != nil && snap.Player2.IsBot {
 			botGames++
-		} else if game.Player2 != nil {
+		} else if snap.Player2 != nil {
 			humanGames++
 		}
-	}
 
-	totalPlayers := len(m.playerGames)
-	connectedPlayers := 0
-	disconnectedPlayers := 0
-
-	for playerID := range m.playerGames {
-		game, err := m.GetGameByPlayer(playerID)
-		if err == nil {
-			if game.Player1 != nil && game.Player1.ID == playerID && game.Player1.Connected {
-				connectedPlayers++
-			} else if game.Player2 != nil && game.Player2.ID == playerID && game.Player2.Connected {
+		for _, p := range []*Player{snap.Player1, snap.Player2} {
+			if p == nil {
+				continue
+			}
+			if p.Connected {
 				connectedPlayers++
 			} else {
 				disconnectedPlayers++
@@ -76,11 +79,15 @@ func (m *Manager) emitSystemMetrics() {
 		}
 	}
 
+	m.mu.RLock()
+	totalPlayers := len(m.playerGames)
+	m.mu.RUnlock()
+
 	// System metrics
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
-	now := time.Now()
+	now := m.clock.Now()
 
 	event := map[string]interface{}{
 		"event_type":    "system_metrics",
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"slices"
+	"time"
+)
+
+// DefaultQueue names the only queue when none are configured
+const DefaultQueue = "standard"
+
+// maxRecordedWaits bounds how many recent waits a queue averages
+const maxRecordedWaits = 50
+
+// BotFallback says who plays a queued player that no human was paired with
+// before the queue's matchmaking timeout
+type BotFallback string
+
+const (
+	BotFallbackEngine BotFallback = "engine" // the external engine if one is registered, else the built-in Bot
+	BotFallbackBot    BotFallback = "bot"    // the built-in Bot, at a strength suited to the player
+	BotFallbackNone   BotFallback = "none"   // nobody: keep waiting for a human
+)
+
+// IsValid reports whether f is one of the fallback policies
+func (f BotFallback) IsValid() bool {
+	switch f {
+	case BotFallbackEngine, BotFallbackBot, BotFallbackNone:
+		return true
+	}
+	return false
+}
+
+// QueueRules configures one matchmaking queue, such as blitz or casual
+type QueueRules struct {
+	Name               string
+	TurnTimeout        time.Duration // a turn in the queue's games is skipped after this long
+	MatchmakingTimeout time.Duration // how long a player waits before BotFallback applies
+	BotFallback        BotFallback
+	Rated              bool // whether the queue's games change ratings
+}
+
+// QueueStats describes a queue and how busy it is
+type QueueStats struct {
+	Name                  string      `json:"name"`
+	PlayersWaiting        int         `json:"players_waiting"`
+	AverageWaitSec        float64     `json:"average_wait_sec"` // over the most recent matches
+	TurnTimeoutSec        int         `json:"turn_timeout_sec"`
+	MatchmakingTimeoutSec int         `json:"matchmaking_timeout_sec"`
+	BotFallback           BotFallback `json:"bot_fallback"`
+	Rated                 bool        `json:"rated"`
+}
+
+// QueueStatus tells a waiting player where they stand in their queue
+type QueueStatus struct {
+	PlayerID      string
+	Queue         string
+	Position      int // 1 for the player who has waited longest
+	Elapsed       time.Duration
+	EstimatedWait time.Duration // how much longer the player is likely to wait
+	BotFallback   BotFallback
+	BotFallbackIn time.Duration // until a bot is found, unless BotFallback is BotFallbackNone
+}
+
+// queue holds the players waiting in one matchmaking queue, in arrival
+// order. It is guarded by Matchmaker.mu.
+type queue struct {
+	rules    QueueRules
+	requests []*MatchRequest
+	waits    []time.Duration // how long recently matched players waited, oldest first
+}
+
+// gameOptions returns the settings of games paired in the queue
+func (q *queue) gameOptions() GameOptions {
+	return GameOptions{
+		Queue:       q.rules.Name,
+		TurnTimeout: q.rules.TurnTimeout,
+		Rated:       q.rules.Rated,
+	}
+}
+
+// remove takes the request at index i out of the queue
+func (q *queue) remove(i int) {
+	q.requests = append(q.requests[:i], q.requests[i+1:]...)
+}
+
+// insert puts request back in arrival order
+func (q *queue) insert(request *MatchRequest) {
+	i, _ := slices.BinarySearchFunc(q.requests, request, func(a, b *MatchRequest) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	q.requests = slices.Insert(q.requests, i, request)
+}
+
+// recordWait notes how long a player waited before being matched
+func (q *queue) recordWait(wait time.Duration) {
+	q.waits = append(q.waits, wait)
+	if len(q.waits) > maxRecordedWaits {
+		q.waits = q.waits[len(q.waits)-maxRecordedWaits:]
+	}
+}
+
+// averageWait returns the mean of the recorded waits, or 0 if there are none
+func (q *queue) averageWait() time.Duration {
+	if len(q.waits) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, wait := range q.waits {
+		total += wait
+	}
+	return total / time.Duration(len(q.waits))
+}
+
+// status describes the position of the request at index i after waiting
+// until now. Without recent matches to go by, the wait is estimated as the
+// queue's matchmaking timeout; a bot fallback caps the estimate.
+func (q *queue) status(i int, now time.Time) QueueStatus {
+	request := q.requests[i]
+	elapsed := now.Sub(request.CreatedAt)
+
+	expected := q.averageWait()
+	if len(q.waits) == 0 {
+		expected = q.rules.MatchmakingTimeout
+	}
+	status := QueueStatus{
+		PlayerID:      request.Player.ID,
+		Queue:         q.rules.Name,
+		Position:      i + 1,
+		Elapsed:       elapsed,
+		EstimatedWait: max(expected-elapsed, 0),
+		BotFallback:   q.rules.BotFallback,
+	}
+	if q.rules.BotFallback != BotFallbackNone {
+		status.BotFallbackIn = max(q.rules.MatchmakingTimeout-elapsed, 0)
+		status.EstimatedWait = min(status.EstimatedWait, status.BotFallbackIn)
+	}
+	return status
+}
+
+func (q *queue) stats() QueueStats {
+	return QueueStats{
+		Name:                  q.rules.Name,
+		PlayersWaiting:        len(q.requests),
+		AverageWaitSec:        q.averageWait().Seconds(),
+		TurnTimeoutSec:        int(q.rules.TurnTimeout / time.Second),
+		MatchmakingTimeoutSec: int(q.rules.MatchmakingTimeout / time.Second),
+		BotFallback:           q.rules.BotFallback,
+		Rated:                 q.rules.Rated,
+	}
+}
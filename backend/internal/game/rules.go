@@ -0,0 +1,20 @@
+package game
+
+import "time"
+
+// Rules holds the timeouts that govern games and matchmaking
+type Rules struct {
+	TurnTimeout           time.Duration // a turn is skipped after this long
+	InactivityTimeout     time.Duration // the player to move forfeits after this long without a move
+	HeartbeatTimeout      time.Duration // a connected player without heartbeats forfeits after this long
+	ReconnectWindow       time.Duration // how long a disconnected player may reconnect
+	FinishedGameRetention time.Duration // how long finished games stay in memory
+	MatchmakingTimeout    time.Duration // queued players are matched with the bot after this long
+	MatchRatingWindow     int           // players are first paired within this many rating points
+	MatchRatingWindowMax  int           // the window never grows beyond this many points
+	MatchWindowWidenEvery time.Duration // the window grows by MatchRatingWindow this often while waiting
+	RematchCooldown       time.Duration // the same two players are not paired again within this long
+	Queues                []QueueRules  // named matchmaking queues, the first being the default
+	QueueStatusInterval   time.Duration // waiting players are told their queue status this often
+	BotMoveDelay          time.Duration // pause before the bot replies
+}
@@ -0,0 +1,187 @@
+package game
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Snapshot is an immutable copy of a game's state, published by the game
+// goroutine after every change. Callers must not modify it.
+type Snapshot struct {
+	ID             string
+	Version        uint64
+	Player1        *Player
+	Player2        *Player
+	Board          *Board
+	CurrentTurn    CellState
+	Status         GameStatus
+	Winner         *Player
+	Result         GameResult
+	CreatedAt      time.Time
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	LastMoveAt     time.Time
+	TurnStartedAt  time.Time
+	TurnTimeoutSec int
+	Queue          string // matchmaking queue, "" for games not paired by the matchmaker
+	Rated          bool
+	Open           bool   // created to be joined by ID rather than paired
+	Moves          []Move // every turn so far, oldest first
+}
+
+// takeSnapshot copies the current state; it must run on the game goroutine
+func (g *Game) takeSnapshot() *Snapshot {
+	snap := &Snapshot{
+		ID:             g.ID,
+		Version:        g.version,
+		Player1:        copyPlayer(g.player1),
+		Player2:        copyPlayer(g.player2),
+		Board:          g.board.Copy(),
+		CurrentTurn:    g.currentTurn,
+		Status:         g.status,
+		Result:         g.result,
+		CreatedAt:      g.createdAt,
+		StartedAt:      copyTime(g.startedAt),
+		FinishedAt:     copyTime(g.finishedAt),
+		LastMoveAt:     g.lastMoveAt,
+		TurnStartedAt:  g.turnStartedAt,
+		TurnTimeoutSec: int(g.turnTimeout / time.Second),
+		Queue:          g.queue,
+		Rated:          g.rated,
+		Open:           g.open,
+		// Moves are only ever appended, so the snapshot can share them
+		Moves: g.moves[:len(g.moves):len(g.moves)],
+	}
+
+	if g.winner != nil {
+		if g.winner == g.player1 {
+			snap.Winner = snap.Player1
+		} else {
+			snap.Winner = snap.Player2
+		}
+	}
+
+	return snap
+}
+
+func copyPlayer(p *Player) *Player {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	cp.DisconnectedAt = copyTime(p.DisconnectedAt)
+	return &cp
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	cp := *t
+	return &cp
+}
+
+// IsOver reports whether the game has been decided or abandoned
+func (s *Snapshot) IsOver() bool {
+	return s.Status == StatusFinished || s.Status == StatusAbandoned
+}
+
+// GetCurrentPlayer returns the player whose turn it is
+func (s *Snapshot) GetCurrentPlayer() *Player {
+	if s.CurrentTurn == Player1 {
+		return s.Player1
+	}
+	return s.Player2
+}
+
+// IsPlayerTurn checks if it's the specified player's turn
+func (s *Snapshot) IsPlayerTurn(playerID string) bool {
+	currentPlayer := s.GetCurrentPlayer()
+	return currentPlayer != nil && currentPlayer.ID == playerID
+}
+
+// IsBotTurn reports whether the server's bot opponent is due to move
+func (s *Snapshot) IsBotTurn() bool {
+	return s.Status == StatusInProgress &&
+		s.Player2 != nil &&
+		s.Player2.Hosted &&
+		s.CurrentTurn == Player2
+}
+
+// GetPlayer returns the player with the given ID, or nil
+func (s *Snapshot) GetPlayer(playerID string) *Player {
+	if s.Player1 != nil && s.Player1.ID == playerID {
+		return s.Player1
+	}
+	if s.Player2 != nil && s.Player2.ID == playerID {
+		return s.Player2
+	}
+	return nil
+}
+
+// MoveCount returns the number of discs on the board
+func (s *Snapshot) MoveCount() int {
+	count := 0
+	for _, row := range s.Board.Grid {
+		for _, cell := range row {
+			if cell != Empty {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// PlayerAt returns the player sitting in seat
+func (s *Snapshot) PlayerAt(seat CellState) *Player {
+	switch seat {
+	case Player1:
+		return s.Player1
+	case Player2:
+		return s.Player2
+	}
+	return nil
+}
+
+// ToJSON converts the snapshot to JSON
+func (s *Snapshot) ToJSON() ([]byte, error) {
+	type GameJSON struct {
+		ID             string     `json:"id"`
+		Player1        *Player    `json:"player1"`
+		Player2        *Player    `json:"player2"`
+		Board          [][]int    `json:"board"`
+		CurrentTurn    int        `json:"current_turn"`
+		Status         GameStatus `json:"status"`
+		Winner         *Player    `json:"winner,omitempty"`
+		Result         GameResult `json:"result,omitempty"`
+		CreatedAt      time.Time  `json:"created_at"`
+		StartedAt      *time.Time `json:"started_at,omitempty"`
+		FinishedAt     *time.Time `json:"finished_at,omitempty"`
+		LastMoveAt     time.Time  `json:"last_move_at"`
+		TurnStartedAt  time.Time  `json:"turn_started_at"`
+		TurnTimeoutSec int        `json:"turn_timeout_sec"`
+		Queue          string     `json:"queue,omitempty"`
+		Rated          bool       `json:"rated"`
+	}
+
+	gameJSON := GameJSON{
+		ID:             s.ID,
+		Player1:        s.Player1,
+		Player2:        s.Player2,
+		Board:          s.Board.ToArray(),
+		CurrentTurn:    int(s.CurrentTurn),
+		Status:         s.Status,
+		Winner:         s.Winner,
+		Result:         s.Result,
+		CreatedAt:      s.CreatedAt,
+		StartedAt:      s.StartedAt,
+		FinishedAt:     s.FinishedAt,
+		LastMoveAt:     s.LastMoveAt,
+		TurnStartedAt:  s.TurnStartedAt,
+		TurnTimeoutSec: s.TurnTimeoutSec,
+		Queue:          s.Queue,
+		Rated:          s.Rated,
+	}
+
+	return json.Marshal(gameJSON)
+}
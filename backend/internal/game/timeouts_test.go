@@ -0,0 +1,423 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/4-in-a-row/internal/clock"
+)
+
+// testStart is when every test clock starts
+var testStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// testRules are the timeouts the tests run under unless they change them
+var testRules = Rules{
+	TurnTimeout:           30 * time.Second,
+	InactivityTimeout:     60 * time.Second,
+	HeartbeatTimeout:      30 * time.Second,
+	ReconnectWindow:       30 * time.Second,
+	FinishedGameRetention: time.Minute,
+	MatchmakingTimeout:    10 * time.Second,
+	QueueStatusInterval:   5 * time.Second,
+	BotMoveDelay:          500 * time.Millisecond,
+}
+
+// newTestPlayer returns a connected human player whose last heartbeat is now
+func newTestPlayer(clk clock.Clock, username string) *Player {
+	return &Player{
+		ID:            uuid.New().String(),
+		Username:      username,
+		SessionToken:  uuid.New().String(),
+		Connected:     true,
+		LastHeartbeat: clk.Now(),
+	}
+}
+
+// newTestBot returns the built-in bot as the matchmaker seats it
+func newTestBot(clk clock.Clock) *Player {
+	return &Player{
+		ID:            uuid.New().String(),
+		Username:      BotUsername,
+		IsBot:         true,
+		Hosted:        true,
+		Connected:     true,
+		LastHeartbeat: clk.Now(),
+	}
+}
+
+// newTestGame starts a game between alice and bob outside any manager, so
+// nothing but the test moves it along
+func newTestGame(t *testing.T) (*Game, *clock.Fake, *Player, *Player) {
+	t.Helper()
+	clk := clock.NewFake(testStart)
+	alice, bob := newTestPlayer(clk, "alice"), newTestPlayer(clk, "bob")
+	game := NewGame(alice, GameOptions{TurnTimeout: testRules.TurnTimeout}, clk)
+	t.Cleanup(game.Stop)
+	if err := game.AddPlayer2(bob); err != nil {
+		t.Fatalf("AddPlayer2: %v", err)
+	}
+	return game, clk, alice, bob
+}
+
+// newTestManager starts a manager under rules without a database or Kafka,
+// returning once its heartbeat and turn timer loops are waiting on a fake
+// clock. The loops stop when the test ends.
+func newTestManager(t *testing.T, rules Rules) (*Manager, *clock.Fake) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clk := clock.NewFake(testStart)
+	m := NewManager(nil, nil, rules, clk)
+	m.Start(ctx)
+	clk.BlockUntil(2)
+	return m, clk
+}
+
+// startTestGame starts a game in m between alice and opponent, alice to move
+func startTestGame(t *testing.T, m *Manager, opponent *Player) (*Game, *Player) {
+	t.Helper()
+	alice := newTestPlayer(m.clock, "alice")
+	game := m.CreateGame(alice)
+	if err := m.JoinGame(game.ID, opponent); err != nil {
+		t.Fatalf("JoinGame: %v", err)
+	}
+	return game, alice
+}
+
+// waitFor returns the first update satisfying cond. The manager's loops run
+// on goroutines of their own, so tests wait for the game to publish the
+// outcome rather than for any amount of time.
+func waitFor(t *testing.T, updates <-chan *Snapshot, cond func(*Snapshot) bool) *Snapshot {
+	t.Helper()
+	for snap := range updates {
+		if cond(snap) {
+			return snap
+		}
+	}
+	t.Fatal("game removed before reaching the expected state")
+	return nil
+}
+
+func TestCheckTurnTimer(t *testing.T) {
+	tests := []struct {
+		name       string
+		wait       time.Duration
+		wantStatus GameStatus
+		wantTurn   CellState
+		wantResult GameResult
+	}{
+		{"within the turn", 30 * time.Second, StatusInProgress, Player1, ""},
+		{"turn timed out", 31 * time.Second, StatusInProgress, Player2, ""},
+		{"at the inactivity limit", 60 * time.Second, StatusInProgress, Player2, ""},
+		{"inactive too long", 61 * time.Second, StatusAbandoned, Player1, ResultPlayer2Win},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, clk, _, _ := newTestGame(t)
+
+			clk.Advance(tt.wait)
+			game.CheckTurnTimer(clk.Now(), testRules.InactivityTimeout)
+
+			snap := game.Snapshot()
+			if snap.Status != tt.wantStatus || snap.CurrentTurn != tt.wantTurn || snap.Result != tt.wantResult {
+				t.Fatalf("status, turn, result = %s, %v, %q, want %s, %v, %q",
+					snap.Status, snap.CurrentTurn, snap.Result, tt.wantStatus, tt.wantTurn, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestExpireHeartbeats(t *testing.T) {
+	tests := []struct {
+		name       string
+		aliceBeat  time.Duration // when alice last sent a heartbeat, 0 for never
+		wait       time.Duration
+		wantResult GameResult
+	}{
+		{"both within the timeout", 0, 30 * time.Second, ""},
+		{"alice silent too long", 0, 31 * time.Second, ResultPlayer2Win},
+		{"bob silent too long", 10 * time.Second, 31 * time.Second, ResultPlayer1Win},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, clk, alice, _ := newTestGame(t)
+
+			if tt.aliceBeat > 0 {
+				clk.Advance(tt.aliceBeat)
+				game.UpdateHeartbeat(alice.ID)
+			}
+			clk.Advance(tt.wait - tt.aliceBeat)
+			game.ExpireHeartbeats(clk.Now(), testRules.HeartbeatTimeout)
+
+			if snap := game.Snapshot(); snap.Result != tt.wantResult {
+				t.Fatalf("result = %q, want %q", snap.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestReconnectWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		away       time.Duration
+		wantErr    error
+		wantResult GameResult
+	}{
+		{"within the window", 10 * time.Second, nil, ""},
+		{"at the window", 30 * time.Second, nil, ""},
+		{"window expired", 31 * time.Second, ErrReconnectExpired, ResultPlayer2Win},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, clk, alice, _ := newTestGame(t)
+
+			game.SetPlayerDisconnected(alice.ID)
+			clk.Advance(tt.away)
+
+			player, err := game.Reconnect(alice.SessionToken, testRules.ReconnectWindow)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reconnect error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (!player.Connected || player.DisconnectedAt != nil) {
+				t.Fatalf("reconnected player = %+v, want connected", player)
+			}
+
+			game.ExpireDisconnected(clk.Now(), testRules.ReconnectWindow)
+			if snap := game.Snapshot(); snap.Result != tt.wantResult {
+				t.Fatalf("result = %q, want %q", snap.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestManagerTimeoutLoops(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjust     func(*Rules)
+		wait       time.Duration
+		silent     bool // alice stops sending heartbeats, bob never does
+		disconnect bool // alice disconnects at the start
+		want       func(*Snapshot) bool
+	}{
+		{
+			name: "turn skipped",
+			wait: 32 * time.Second,
+			want: func(s *Snapshot) bool {
+				return s.Status == StatusInProgress && s.CurrentTurn == Player2
+			},
+		},
+		{
+			name:   "inactive too long",
+			adjust: func(r *Rules) { r.TurnTimeout = 2 * time.Minute },
+			wait:   62 * time.Second,
+			want: func(s *Snapshot) bool {
+				return s.Status == StatusAbandoned && s.Result == ResultPlayer2Win
+			},
+		},
+		{
+			name:   "heartbeats stopped",
+			wait:   35 * time.Second,
+			silent: true,
+			want: func(s *Snapshot) bool {
+				return s.Status == StatusAbandoned && s.Result == ResultPlayer2Win
+			},
+		},
+		{
+			name:       "reconnect window expired",
+			adjust:     func(r *Rules) { r.HeartbeatTimeout = time.Hour },
+			wait:       35 * time.Second,
+			disconnect: true,
+			want: func(s *Snapshot) bool {
+				return s.Status == StatusAbandoned && s.Result == ResultPlayer2Win
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := testRules
+			if tt.adjust != nil {
+				tt.adjust(&rules)
+			}
+			m, clk := newTestManager(t, rules)
+			bob := newTestPlayer(clk, "bob")
+			game, alice := startTestGame(t, m, bob)
+			updates, unsubscribe := game.Subscribe()
+			defer unsubscribe()
+
+			if tt.disconnect {
+				m.SetPlayerDisconnected(alice.ID)
+			}
+			for waited := time.Duration(0); waited < tt.wait; {
+				step := min(10*time.Second, tt.wait-waited)
+				clk.Advance(step)
+				waited += step
+				if !tt.silent && !tt.disconnect {
+					game.UpdateHeartbeat(alice.ID)
+				}
+				game.UpdateHeartbeat(bob.ID)
+			}
+
+			waitFor(t, updates, tt.want)
+		})
+	}
+}
+
+func TestFinishedGameRetention(t *testing.T) {
+	tests := []struct {
+		name        string
+		wait        time.Duration
+		wantRemoved bool
+	}{
+		{"within retention", testRules.FinishedGameRetention - time.Second, false},
+		{"retention over", testRules.FinishedGameRetention, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clk := newTestManager(t, testRules)
+			game, alice := startTestGame(t, m, newTestPlayer(clk, "bob"))
+			updates, unsubscribe := game.Subscribe()
+			defer unsubscribe()
+
+			game.AbandonGame(alice.ID)
+			clk.BlockUntil(3) // the retention timer
+			clk.Advance(tt.wait)
+
+			if tt.wantRemoved {
+				for range updates {
+					// Drained once the game is stopped
+				}
+			}
+			if _, err := m.GetGame(game.ID); (err != nil) != tt.wantRemoved {
+				t.Fatalf("GetGame error = %v, want removed %v", err, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestBotMoveDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		wait      time.Duration
+		wantMoved bool
+	}{
+		{"thinking", testRules.BotMoveDelay - time.Millisecond, false},
+		{"delay over", testRules.BotMoveDelay, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clk := newTestManager(t, testRules)
+			game, alice := startTestGame(t, m, newTestBot(clk))
+			updates, unsubscribe := game.Subscribe()
+			defer unsubscribe()
+
+			if _, err := m.MakeMove(game.ID, alice.ID, 3); err != nil {
+				t.Fatalf("MakeMove: %v", err)
+			}
+			clk.BlockUntil(3) // the bot's delay
+			clk.Advance(tt.wait)
+
+			if tt.wantMoved {
+				waitFor(t, updates, func(s *Snapshot) bool { return s.CurrentTurn == Player1 })
+			} else if snap := game.Snapshot(); snap.CurrentTurn != Player2 {
+				t.Fatal("bot moved before its delay was over")
+			}
+		})
+	}
+}
+
+func TestMatchmakingBotFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		wait    time.Duration
+		wantBot bool
+	}{
+		{"still searching", testRules.MatchmakingTimeout - time.Second, false},
+		{"search timed out", testRules.MatchmakingTimeout, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clk := newTestManager(t, testRules)
+			mm := NewMatchmaker(m, testRules)
+
+			game, matched, err := mm.AddPlayer(m.NewPlayer("alice"), "")
+			if err != nil || matched {
+				t.Fatalf("AddPlayer = %v, %v, want queued", matched, err)
+			}
+			clk.Advance(tt.wait)
+			mm.processQueue()
+
+			snap := game.Snapshot()
+			if gotBot := snap.Player2 != nil && snap.Player2.Hosted; gotBot != tt.wantBot {
+				t.Fatalf("matched with the bot = %v, want %v", gotBot, tt.wantBot)
+			}
+		})
+	}
+}
+
+func TestMatchmakerRun(t *testing.T) {
+	m, clk := newTestManager(t, testRules)
+	mm := NewMatchmaker(m, testRules)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mm.Run(ctx)
+	clk.BlockUntil(3)
+
+	game, _, err := mm.AddPlayer(m.NewPlayer("alice"), "")
+	if err != nil {
+		t.Fatalf("AddPlayer: %v", err)
+	}
+	updates, unsubscribe := game.Subscribe()
+	defer unsubscribe()
+
+	clk.Advance(testRules.MatchmakingTimeout)
+	waitFor(t, updates, func(s *Snapshot) bool {
+		return s.Status == StatusInProgress && s.Player2 != nil && s.Player2.Hosted
+	})
+}
+
+func TestQueueStatusInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		wait      time.Duration
+		wantTotal int // statuses published, counting the first one
+	}{
+		{"within the interval", testRules.QueueStatusInterval - time.Second, 1},
+		{"interval over", testRules.QueueStatusInterval, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clk := newTestManager(t, testRules)
+			mm := NewMatchmaker(m, testRules)
+			var statuses []QueueStatus
+			mm.SetQueueStatusCallback(func(status QueueStatus) {
+				statuses = append(statuses, status)
+			})
+			if _, _, err := mm.AddPlayer(m.NewPlayer("alice"), ""); err != nil {
+				t.Fatalf("AddPlayer: %v", err)
+			}
+
+			mm.publishQueueStatus()
+			clk.Advance(tt.wait)
+			mm.publishQueueStatus()
+
+			if len(statuses) != tt.wantTotal {
+				t.Fatalf("published %d statuses, want %d", len(statuses), tt.wantTotal)
+			}
+			last := statuses[len(statuses)-1]
+			if want := testRules.MatchmakingTimeout - last.Elapsed; last.BotFallbackIn != want {
+				t.Fatalf("bot fallback in %v, want %v", last.BotFallbackIn, want)
+			}
+		})
+	}
+}
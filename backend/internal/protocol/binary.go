@@ -0,0 +1,314 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/protocol/pb"
+)
+
+// Subprotocols a client may request in Sec-WebSocket-Protocol. Clients that
+// request none speak JSON.
+const (
+	SubprotocolJSON     = "4inarow.v1.json"
+	SubprotocolProtobuf = "4inarow.v1.protobuf"
+)
+
+// GameDelta is sent to protobuf clients instead of a full game_update when
+// they already hold the previous state. It carries the discs placed since
+// that state and the fields that change every turn; players joining,
+// leaving or winning are reported by their own events.
+type GameDelta struct {
+	Discs         []Disc    `json:"discs"`
+	CurrentTurn   int       `json:"current_turn"`
+	Status        string    `json:"status"`
+	LastMoveAt    time.Time `json:"last_move_at"`
+	TurnStartedAt time.Time `json:"turn_started_at"`
+}
+
+// Disc is a disc placed on the board
+type Disc struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+	Seat   int `json:"seat"`
+}
+
+// NewGameDelta describes the change from prev to cur, or returns nil when a
+// client needs the full state
+func NewGameDelta(prev, cur *game.Snapshot) *GameDelta {
+	if prev == nil || prev.Player2 == nil || cur.Player2 == nil || len(cur.Moves) < len(prev.Moves) {
+		return nil
+	}
+
+	delta := &GameDelta{
+		Discs:         []Disc{},
+		CurrentTurn:   int(cur.CurrentTurn),
+		Status:        string(cur.Status),
+		LastMoveAt:    cur.LastMoveAt,
+		TurnStartedAt: cur.TurnStartedAt,
+	}
+	for _, move := range cur.Moves[len(prev.Moves):] {
+		if move.Skipped {
+			continue
+		}
+		delta.Discs = append(delta.Discs, Disc{Row: move.Row, Column: move.Column, Seat: int(move.Seat)})
+	}
+	return delta
+}
+
+// EncodeBinary encodes a server message for protobuf clients. A game_update
+// that carries a Delta is sent as a game_delta.
+func EncodeBinary(env ServerEnvelope) ([]byte, error) {
+	msg := &pb.ServerMessage{Seq: env.Seq}
+
+	switch p := env.Payload.(type) {
+	case Welcome:
+		msg.Msg = &pb.ServerMessage_Welcome{Welcome: &pb.Welcome{
+			Version:           int32(p.Version),
+			SupportedVersions: int32s(p.SupportedVersions),
+		}}
+	case PlayerInfo:
+		msg.Msg = &pb.ServerMessage_PlayerInfo{PlayerInfo: pbPlayerInfo(p)}
+	case Reconnected:
+		msg.Msg = &pb.ServerMessage_Reconnected{Reconnected: pbPlayerInfo(PlayerInfo(p))}
+	case Waiting:
+		msg.Msg = &pb.ServerMessage_Waiting{Waiting: &pb.Waiting{Message: p.Message}}
+	case QueueStatus:
+		status := &pb.QueueStatus{
+			Queue:            p.Queue,
+			Position:         int32(p.Position),
+			ElapsedSec:       int32(p.ElapsedSec),
+			EstimatedWaitSec: int32(p.EstimatedWaitSec),
+		}
+		if p.BotFallbackInSec != nil {
+			fallbackIn := int32(*p.BotFallbackInSec)
+			status.BotFallbackInSec = &fallbackIn
+		}
+		msg.Msg = &pb.ServerMessage_QueueStatus{QueueStatus: status}
+	case SearchCancelled:
+		msg.Msg = &pb.ServerMessage_SearchCancelled{SearchCancelled: &pb.Notice{Message: p.Message}}
+	case SessionReplaced:
+		msg.Msg = &pb.ServerMessage_SessionReplaced{SessionReplaced: &pb.Notice{Message: p.Message}}
+	case HeartbeatAck:
+		msg.Msg = &pb.ServerMessage_HeartbeatAck{HeartbeatAck: &pb.HeartbeatAck{Seq: p.Seq}}
+	case ServerDraining:
+		msg.Msg = &pb.ServerMessage_ServerDraining{ServerDraining: &pb.ServerDraining{
+			Message:        p.Message,
+			DeadlineUnixMs: unixMs(p.Deadline),
+		}}
+	case Error:
+		msg.Msg = &pb.ServerMessage_Error{Error: &pb.Error{Code: string(p.Code), Message: p.Message}}
+	case GameState:
+		if env.Delta != nil {
+			msg.Msg = &pb.ServerMessage_GameDelta{GameDelta: pbGameDelta(env.Delta)}
+		} else {
+			msg.Msg = &pb.ServerMessage_GameUpdate{GameUpdate: pbGameState(p)}
+		}
+	case GameStarted:
+		msg.Msg = &pb.ServerMessage_GameStarted{GameStarted: &pb.GameStarted{
+			Player1:     pbPlayerState(p.Player1),
+			Player2:     pbPlayerState(p.Player2),
+			CurrentTurn: int32(p.CurrentTurn),
+		}}
+	case MoveMade:
+		msg.Msg = &pb.ServerMessage_MoveMade{MoveMade: &pb.MoveMade{
+			MoveNumber: int32(p.MoveNumber),
+			PlayerId:   p.PlayerID,
+			Seat:       int32(p.Seat),
+			Column:     int32(p.Column),
+			Row:        int32(p.Row),
+		}}
+	case TurnSkipped:
+		msg.Msg = &pb.ServerMessage_TurnSkipped{TurnSkipped: &pb.TurnSkipped{
+			MoveNumber: int32(p.MoveNumber),
+			PlayerId:   p.PlayerID,
+			Seat:       int32(p.Seat),
+		}}
+	case GameOver:
+		msg.Msg = &pb.ServerMessage_GameOver{GameOver: &pb.GameOver{
+			Status: p.Status,
+			Result: p.Result,
+			Winner: pbPlayerState(p.Winner),
+		}}
+	case OpponentDisconnected:
+		msg.Msg = &pb.ServerMessage_OpponentDisconnected{OpponentDisconnected: &pb.OpponentDisconnected{
+			PlayerId:            p.PlayerID,
+			Username:            p.Username,
+			ForfeitAtUnixMs:     unixMs(p.ForfeitAt),
+			SecondsUntilForfeit: int32(p.SecondsUntilForfeit),
+		}}
+	case OpponentReconnected:
+		msg.Msg = &pb.ServerMessage_OpponentReconnected{OpponentReconnected: &pb.OpponentReconnected{
+			PlayerId: p.PlayerID,
+			Username: p.Username,
+		}}
+	case ArenaLeaderboard:
+		board := &pb.ArenaLeaderboard{
+			ArenaId:      p.ArenaID,
+			Name:         p.Name,
+			Status:       p.Status,
+			EndsAtUnixMs: unixMs(p.EndsAt),
+			RemainingSec: int32(p.RemainingSec),
+		}
+		for _, s := range p.Standings {
+			board.Standings = append(board.Standings, &pb.ArenaStanding{
+				Rank:     int32(s.Rank),
+				Username: s.Username,
+				Score:    int32(s.Score),
+				Games:    int32(s.Games),
+				Wins:     int32(s.Wins),
+				Draws:    int32(s.Draws),
+				Losses:   int32(s.Losses),
+				Streak:   int32(s.Streak),
+				OnFire:   s.OnFire,
+			})
+		}
+		msg.Msg = &pb.ServerMessage_ArenaLeaderboard{ArenaLeaderboard: board}
+	default:
+		return nil, fmt.Errorf("no protobuf encoding for %s payload %T", env.Type, env.Payload)
+	}
+
+	return proto.Marshal(msg)
+}
+
+// DecodeBinaryClient parses and validates a protobuf client message, like
+// DecodeClient does for JSON
+func DecodeBinaryClient(data []byte) (string, ClientPayload, error) {
+	var msg pb.ClientMessage
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	var msgType string
+	var payload ClientPayload
+	switch m := msg.Msg.(type) {
+	case *pb.ClientMessage_Hello:
+		versions := make([]int, len(m.Hello.GetVersions()))
+		for i, v := range m.Hello.GetVersions() {
+			versions[i] = int(v)
+		}
+		msgType, payload = TypeHello, &Hello{Versions: versions}
+	case *pb.ClientMessage_Join:
+		msgType, payload = TypeJoin, &Join{
+			Username: m.Join.GetUsername(),
+			Token:    m.Join.GetToken(),
+			Guest:    m.Join.GetGuest(),
+			Queue:    m.Join.GetQueue(),
+		}
+	case *pb.ClientMessage_Move:
+		move := &Move{}
+		if m.Move.Column != nil {
+			column := int(m.Move.GetColumn())
+			move.Column = &column
+		}
+		msgType, payload = TypeMove, move
+	case *pb.ClientMessage_Reconnect:
+		msgType, payload = TypeReconnect, &Reconnect{
+			SessionToken: m.Reconnect.GetSessionToken(),
+			LastSeq:      m.Reconnect.LastSeq,
+		}
+	case *pb.ClientMessage_Heartbeat:
+		msgType, payload = TypeHeartbeat, &Heartbeat{}
+	case *pb.ClientMessage_Ack:
+		msgType, payload = TypeAck, &Ack{Seq: m.Ack.GetSeq()}
+	case *pb.ClientMessage_Resync:
+		msgType, payload = TypeResync, &Resync{FromSeq: m.Resync.GetFromSeq()}
+	case *pb.ClientMessage_CancelSearch:
+		msgType, payload = TypeCancelSearch, &CancelSearch{}
+	default:
+		return "", nil, fmt.Errorf("%w: no message set", ErrInvalidMessage)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return msgType, nil, fmt.Errorf("%w: %s payload: %v", ErrInvalidMessage, msgType, err)
+	}
+	return msgType, payload, nil
+}
+
+func pbPlayerInfo(p PlayerInfo) *pb.PlayerInfo {
+	return &pb.PlayerInfo{
+		PlayerId:     p.PlayerID,
+		GameId:       p.GameID,
+		Username:     p.Username,
+		SessionToken: p.SessionToken,
+	}
+}
+
+func pbPlayerState(p *PlayerState) *pb.PlayerState {
+	if p == nil {
+		return nil
+	}
+	return &pb.PlayerState{
+		Id:        p.ID,
+		Username:  p.Username,
+		IsBot:     p.IsBot,
+		Connected: p.Connected,
+	}
+}
+
+func pbGameState(s GameState) *pb.GameState {
+	board := make([]byte, 0, game.Rows*game.Columns)
+	for _, row := range s.Board {
+		for _, cell := range row {
+			board = append(board, byte(cell))
+		}
+	}
+
+	state := &pb.GameState{
+		Id:                  s.ID,
+		Player1:             pbPlayerState(s.Player1),
+		Player2:             pbPlayerState(s.Player2),
+		Board:               board,
+		CurrentTurn:         int32(s.CurrentTurn),
+		Status:              s.Status,
+		Winner:              pbPlayerState(s.Winner),
+		Result:              s.Result,
+		CreatedAtUnixMs:     unixMs(s.CreatedAt),
+		LastMoveAtUnixMs:    unixMs(s.LastMoveAt),
+		TurnStartedAtUnixMs: unixMs(s.TurnStartedAt),
+		TurnTimeoutSec:      int32(s.TurnTimeoutSec),
+	}
+	if s.StartedAt != nil {
+		state.StartedAtUnixMs = unixMs(*s.StartedAt)
+	}
+	if s.FinishedAt != nil {
+		state.FinishedAtUnixMs = unixMs(*s.FinishedAt)
+	}
+	return state
+}
+
+func pbGameDelta(d *GameDelta) *pb.GameDelta {
+	delta := &pb.GameDelta{
+		CurrentTurn:         int32(d.CurrentTurn),
+		Status:              d.Status,
+		LastMoveAtUnixMs:    unixMs(d.LastMoveAt),
+		TurnStartedAtUnixMs: unixMs(d.TurnStartedAt),
+	}
+	for _, disc := range d.Discs {
+		delta.Discs = append(delta.Discs, &pb.Disc{
+			Row:    int32(disc.Row),
+			Column: int32(disc.Column),
+			Seat:   int32(disc.Seat),
+		})
+	}
+	return delta
+}
+
+// unixMs converts t to milliseconds since the epoch, keeping the zero time 0
+func unixMs(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func int32s(values []int) []int32 {
+	out := make([]int32, len(values))
+	for i, v := range values {
+		out[i] = int32(v)
+	}
+	return out
+}
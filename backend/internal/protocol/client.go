@@ -0,0 +1,150 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ClientPayload is the payload of a client message
+type ClientPayload interface {
+	Validate() error
+}
+
+// Hello opens the handshake, listing the protocol versions the client speaks
+type Hello struct {
+	Versions []int `json:"versions"`
+}
+
+func (h *Hello) Validate() error {
+	if len(h.Versions) == 0 {
+		return errors.New("versions is required")
+	}
+	return nil
+}
+
+// Join enters matchmaking. Registered players send the token from
+// /api/auth/login, which names them; guests set Guest and pick a username
+// that no account owns. Bot accounts authenticate outside the payload.
+// Queue names the matchmaking queue, see /api/queues; it defaults to the
+// first one.
+type Join struct {
+	Username string `json:"username,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Guest    bool   `json:"guest,omitempty"`
+	Queue    string `json:"queue,omitempty"`
+}
+
+func (j *Join) Validate() error {
+	if j.Token != "" && j.Guest {
+		return errors.New("token and guest are mutually exclusive")
+	}
+	if j.Username == "" && j.Token == "" {
+		return errors.New("username is required")
+	}
+	if len(j.Username) > 32 {
+		return errors.New("username must be at most 32 characters")
+	}
+	if len(j.Queue) > 32 {
+		return errors.New("queue must be at most 32 characters")
+	}
+	return nil
+}
+
+// Move drops a disc into a column (0-6)
+type Move struct {
+	Column *int `json:"column"`
+}
+
+func (m *Move) Validate() error {
+	if m.Column == nil {
+		return errors.New("column is required")
+	}
+	return nil
+}
+
+// Reconnect resumes a game. LastSeq is the sequence number of the last game
+// event the client processed; the events after it are replayed.
+type Reconnect struct {
+	SessionToken string  `json:"session_token"`
+	LastSeq      *uint64 `json:"last_seq,omitempty"`
+}
+
+func (r *Reconnect) Validate() error {
+	if r.SessionToken == "" {
+		return errors.New("session_token is required")
+	}
+	return nil
+}
+
+// Heartbeat keeps the player's connection alive
+type Heartbeat struct{}
+
+func (h *Heartbeat) Validate() error {
+	return nil
+}
+
+// Ack acknowledges every game event up to Seq
+type Ack struct {
+	Seq uint64 `json:"seq"`
+}
+
+func (a *Ack) Validate() error {
+	return nil
+}
+
+// Resync asks for the game events after FromSeq
+type Resync struct {
+	FromSeq uint64 `json:"from_seq"`
+}
+
+func (r *Resync) Validate() error {
+	return nil
+}
+
+// CancelSearch leaves matchmaking before an opponent is found
+type CancelSearch struct{}
+
+func (c *CancelSearch) Validate() error {
+	return nil
+}
+
+// DecodeClient parses and validates a client message. Unknown message types
+// and unknown fields are rejected.
+func DecodeClient(data []byte) (string, ClientPayload, error) {
+	var envelope ClientEnvelope
+	if err := strictUnmarshal(data, &envelope); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	newPayload, ok := ClientMessages[envelope.Type]
+	if !ok {
+		return envelope.Type, nil, fmt.Errorf("%w %q", ErrUnknownMessageType, envelope.Type)
+	}
+
+	payload := newPayload()
+	if len(envelope.Payload) > 0 && !bytes.Equal(envelope.Payload, []byte("null")) {
+		if err := strictUnmarshal(envelope.Payload, payload); err != nil {
+			return envelope.Type, nil, fmt.Errorf("%w: %s payload: %v", ErrInvalidMessage, envelope.Type, err)
+		}
+	}
+
+	if err := payload.Validate(); err != nil {
+		return envelope.Type, nil, fmt.Errorf("%w: %s payload: %v", ErrInvalidMessage, envelope.Type, err)
+	}
+
+	return envelope.Type, payload, nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after message")
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package protocol
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/yourusername/4-in-a-row/internal/auth"
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+	"github.com/yourusername/4-in-a-row/internal/tournament"
+)
+
+var (
+	ErrInvalidMessage     = errors.New("invalid message")
+	ErrUnknownMessageType = errors.New("unknown message type")
+	ErrUnsupportedVersion = errors.New("unsupported protocol version")
+
+	// Auth
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrAdminDisabled = errors.New("admin API disabled")
+)
+
+// ErrorCode is a stable, machine-readable error identifier. Codes are sent
+// in WebSocket error messages and REST error bodies; unlike the messages
+// next to them they never change once published.
+type ErrorCode string
+
+const (
+	CodeInvalidMessage     ErrorCode = "invalid_message"
+	CodeUnknownMessageType ErrorCode = "unknown_message_type"
+	CodeUnsupportedVersion ErrorCode = "unsupported_version"
+	CodeGameNotFound       ErrorCode = "game_not_found"
+	CodeGameNotInProgress  ErrorCode = "game_not_in_progress"
+	CodeInvalidPlayer      ErrorCode = "invalid_player"
+	CodeNotYourTurn        ErrorCode = "not_your_turn"
+	CodeInvalidMove        ErrorCode = "invalid_move"
+	CodeColumnFull         ErrorCode = "column_full"
+	CodePlayerNotInGame    ErrorCode = "player_not_in_game"
+	CodeNotInGame          ErrorCode = "not_in_game"
+	CodeGameFull           ErrorCode = "game_full"
+	CodeSelfMatch          ErrorCode = "self_match"
+	CodeGameNotOpen        ErrorCode = "game_not_open"
+	CodeSessionNotFound    ErrorCode = "session_not_found"
+	CodeReconnectExpired   ErrorCode = "reconnect_expired"
+	CodeServerDraining     ErrorCode = "server_draining"
+	CodeMatchFailed        ErrorCode = "match_failed"
+	CodeUnknownQueue       ErrorCode = "unknown_queue"
+	CodeNotSearching       ErrorCode = "not_searching"
+	CodeAlreadyPlaying     ErrorCode = "already_playing"
+	CodeTournamentNotFound ErrorCode = "tournament_not_found"
+	CodeRegistrationClosed ErrorCode = "registration_closed"
+	CodeAlreadyRegistered  ErrorCode = "already_registered"
+	CodeNotRegistered      ErrorCode = "not_registered"
+	CodeTooFewPlayers      ErrorCode = "too_few_players"
+	CodeNoTournamentGame   ErrorCode = "no_tournament_game"
+	CodeNoBracket          ErrorCode = "no_bracket"
+	CodeArenaNotFound      ErrorCode = "arena_not_found"
+	CodeSeasonNotFound     ErrorCode = "season_not_found"
+	CodeUserNotFound       ErrorCode = "user_not_found"
+	CodeUsernameTaken      ErrorCode = "username_taken"
+	CodeUnauthorized       ErrorCode = "unauthorized"
+	CodeInvalidToken       ErrorCode = "invalid_token"
+	CodeInvalidCredentials ErrorCode = "invalid_credentials"
+	CodeAdminDisabled      ErrorCode = "admin_disabled"
+	CodeInternal           ErrorCode = "internal_error"
+)
+
+// errorCatalogue maps sentinel errors to their code and HTTP status. Errors
+// are matched with errors.Is, so wrapped sentinels keep their code.
+var errorCatalogue = []struct {
+	err    error
+	code   ErrorCode
+	status int
+}{
+	{ErrInvalidMessage, CodeInvalidMessage, http.StatusBadRequest},
+	{ErrUnknownMessageType, CodeUnknownMessageType, http.StatusBadRequest},
+	{ErrUnsupportedVersion, CodeUnsupportedVersion, http.StatusBadRequest},
+	{game.ErrGameNotFound, CodeGameNotFound, http.StatusNotFound},
+	{game.ErrGameNotInProgress, CodeGameNotInProgress, http.StatusConflict},
+	{game.ErrInvalidPlayer, CodeInvalidPlayer, http.StatusForbidden},
+	{game.ErrNotYourTurn, CodeNotYourTurn, http.StatusConflict},
+	{game.ErrInvalidMove, CodeInvalidMove, http.StatusBadRequest},
+	{game.ErrColumnFull, CodeColumnFull, http.StatusConflict},
+	{game.ErrPlayerNotInGame, CodePlayerNotInGame, http.StatusNotFound},
+	{game.ErrNotInGame, CodeNotInGame, http.StatusConflict},
+	{game.ErrGameFull, CodeGameFull, http.StatusConflict},
+	{game.ErrSelfMatch, CodeSelfMatch, http.StatusConflict},
+	{game.ErrGameNotOpen, CodeGameNotOpen, http.StatusConflict},
+	{game.ErrSessionNotFound, CodeSessionNotFound, http.StatusNotFound},
+	{game.ErrReconnectExpired, CodeReconnectExpired, http.StatusGone},
+	{game.ErrServerDraining, CodeServerDraining, http.StatusServiceUnavailable},
+	{game.ErrMatchFailed, CodeMatchFailed, http.StatusInternalServerError},
+	{game.ErrUnknownQueue, CodeUnknownQueue, http.StatusNotFound},
+	{game.ErrNotSearching, CodeNotSearching, http.StatusConflict},
+	{game.ErrAlreadyPlaying, CodeAlreadyPlaying, http.StatusConflict},
+	{database.ErrTournamentNotFound, CodeTournamentNotFound, http.StatusNotFound},
+	{tournament.ErrRegistrationClosed, CodeRegistrationClosed, http.StatusConflict},
+	{tournament.ErrAlreadyRegistered, CodeAlreadyRegistered, http.StatusConflict},
+	{tournament.ErrNotRegistered, CodeNotRegistered, http.StatusNotFound},
+	{tournament.ErrTooFewPlayers, CodeTooFewPlayers, http.StatusConflict},
+	{tournament.ErrNoTournamentGame, CodeNoTournamentGame, http.StatusNotFound},
+	{tournament.ErrNoBracket, CodeNoBracket, http.StatusNotFound},
+	{database.ErrArenaNotFound, CodeArenaNotFound, http.StatusNotFound},
+	{database.ErrSeasonNotFound, CodeSeasonNotFound, http.StatusNotFound},
+	{database.ErrUserNotFound, CodeUserNotFound, http.StatusNotFound},
+	{database.ErrUsernameTaken, CodeUsernameTaken, http.StatusConflict},
+	{ErrUnauthorized, CodeUnauthorized, http.StatusUnauthorized},
+	{auth.ErrInvalidToken, CodeInvalidToken, http.StatusUnauthorized},
+	{auth.ErrInvalidCredentials, CodeInvalidCredentials, http.StatusUnauthorized},
+	{ErrAdminDisabled, CodeAdminDisabled, http.StatusNotFound},
+}
+
+// ErrorCodes lists every code a client may receive
+func ErrorCodes() []ErrorCode {
+	codes := make([]ErrorCode, 0, len(errorCatalogue)+1)
+	for _, entry := range errorCatalogue {
+		codes = append(codes, entry.code)
+	}
+	return append(codes, CodeInternal)
+}
+
+// CodeOf returns the code of err, or CodeInternal if it is not in the
+// catalogue
+func CodeOf(err error) ErrorCode {
+	for _, entry := range errorCatalogue {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return CodeInternal
+}
+
+// HTTPStatus returns the HTTP status REST endpoints answer with for code
+func (c ErrorCode) HTTPStatus() int {
+	for _, entry := range errorCatalogue {
+		if entry.code == c {
+			return entry.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// NewError builds the error payload for err. Errors outside the catalogue
+// are reported as internal errors without leaking their message.
+func NewError(err error) Error {
+	code := CodeOf(err)
+	if code == CodeInternal {
+		return Error{Code: code, Message: "internal server error"}
+	}
+	return Error{Code: code, Message: err.Error()}
+}
@@ -0,0 +1,731 @@
+// gRPC API for bots and internal services. Calls other than FindMatch,
+// CreateGame, JoinGame and AnalyzePosition authenticate with the session
+// token they return, sent as "authorization: Bearer <token>" metadata.
+//
+// Regenerate game_service.pb.go and game_service_grpc.pb.go after editing:
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative game_service.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: game_service.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Requests that enter a game identify the player like a WebSocket join:
+// with an account token, as a guest, or with a bot API key in metadata.
+type FindMatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Guest         bool                   `protobuf:"varint,3,opt,name=guest,proto3" json:"guest,omitempty"`
+	Queue         string                 `protobuf:"bytes,4,opt,name=queue,proto3" json:"queue,omitempty"` // matchmaking queue, the default one if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMatchRequest) Reset() {
+	*x = FindMatchRequest{}
+	mi := &file_game_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMatchRequest) ProtoMessage() {}
+
+func (x *FindMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMatchRequest.ProtoReflect.Descriptor instead.
+func (*FindMatchRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FindMatchRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FindMatchRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *FindMatchRequest) GetGuest() bool {
+	if x != nil {
+		return x.Guest
+	}
+	return false
+}
+
+func (x *FindMatchRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type CreateGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Guest         bool                   `protobuf:"varint,3,opt,name=guest,proto3" json:"guest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGameRequest) Reset() {
+	*x = CreateGameRequest{}
+	mi := &file_game_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameRequest) ProtoMessage() {}
+
+func (x *CreateGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameRequest.ProtoReflect.Descriptor instead.
+func (*CreateGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateGameRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateGameRequest) GetGuest() bool {
+	if x != nil {
+		return x.Guest
+	}
+	return false
+}
+
+type JoinGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Guest         bool                   `protobuf:"varint,4,opt,name=guest,proto3" json:"guest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinGameRequest) Reset() {
+	*x = JoinGameRequest{}
+	mi := &file_game_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameRequest) ProtoMessage() {}
+
+func (x *JoinGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameRequest.ProtoReflect.Descriptor instead.
+func (*JoinGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *JoinGameRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *JoinGameRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *JoinGameRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *JoinGameRequest) GetGuest() bool {
+	if x != nil {
+		return x.Guest
+	}
+	return false
+}
+
+type GameSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Player        *PlayerInfo            `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	Game          *GameState             `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameSession) Reset() {
+	*x = GameSession{}
+	mi := &file_game_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSession) ProtoMessage() {}
+
+func (x *GameSession) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSession.ProtoReflect.Descriptor instead.
+func (*GameSession) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GameSession) GetPlayer() *PlayerInfo {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *GameSession) GetGame() *GameState {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+type GetGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGameRequest) Reset() {
+	*x = GetGameRequest{}
+	mi := &file_game_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameRequest) ProtoMessage() {}
+
+func (x *GetGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameRequest.ProtoReflect.Descriptor instead.
+func (*GetGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{4}
+}
+
+type WatchGameRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after this event instead of starting from the full state
+	LastSeq       *uint64 `protobuf:"varint,1,opt,name=last_seq,json=lastSeq,proto3,oneof" json:"last_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchGameRequest) Reset() {
+	*x = WatchGameRequest{}
+	mi := &file_game_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGameRequest) ProtoMessage() {}
+
+func (x *WatchGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGameRequest.ProtoReflect.Descriptor instead.
+func (*WatchGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchGameRequest) GetLastSeq() uint64 {
+	if x != nil && x.LastSeq != nil {
+		return *x.LastSeq
+	}
+	return 0
+}
+
+type MakeMoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Column        int32                  `protobuf:"varint,1,opt,name=column,proto3" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakeMoveRequest) Reset() {
+	*x = MakeMoveRequest{}
+	mi := &file_game_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakeMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeMoveRequest) ProtoMessage() {}
+
+func (x *MakeMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeMoveRequest.ProtoReflect.Descriptor instead.
+func (*MakeMoveRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MakeMoveRequest) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+type ResignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResignRequest) Reset() {
+	*x = ResignRequest{}
+	mi := &file_game_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResignRequest) ProtoMessage() {}
+
+func (x *ResignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResignRequest.ProtoReflect.Descriptor instead.
+func (*ResignRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{7}
+}
+
+type AnalyzePositionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Columns played so far from the empty board, player 1 first
+	Moves         []int32 `protobuf:"varint,1,rep,packed,name=moves,proto3" json:"moves,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzePositionRequest) Reset() {
+	*x = AnalyzePositionRequest{}
+	mi := &file_game_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzePositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzePositionRequest) ProtoMessage() {}
+
+func (x *AnalyzePositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzePositionRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzePositionRequest) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnalyzePositionRequest) GetMoves() []int32 {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+type AnalyzePositionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ToMove        int32                  `protobuf:"varint,1,opt,name=to_move,json=toMove,proto3" json:"to_move,omitempty"`
+	BestColumn    int32                  `protobuf:"varint,2,opt,name=best_column,json=bestColumn,proto3" json:"best_column,omitempty"`
+	Scores        []*ColumnScore         `protobuf:"bytes,3,rep,name=scores,proto3" json:"scores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzePositionResponse) Reset() {
+	*x = AnalyzePositionResponse{}
+	mi := &file_game_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzePositionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzePositionResponse) ProtoMessage() {}
+
+func (x *AnalyzePositionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzePositionResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzePositionResponse) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnalyzePositionResponse) GetToMove() int32 {
+	if x != nil {
+		return x.ToMove
+	}
+	return 0
+}
+
+func (x *AnalyzePositionResponse) GetBestColumn() int32 {
+	if x != nil {
+		return x.BestColumn
+	}
+	return 0
+}
+
+func (x *AnalyzePositionResponse) GetScores() []*ColumnScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+type ColumnScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Column        int32                  `protobuf:"varint,1,opt,name=column,proto3" json:"column,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColumnScore) Reset() {
+	*x = ColumnScore{}
+	mi := &file_game_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnScore) ProtoMessage() {}
+
+func (x *ColumnScore) ProtoReflect() protoreflect.Message {
+	mi := &file_game_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnScore.ProtoReflect.Descriptor instead.
+func (*ColumnScore) Descriptor() ([]byte, []int) {
+	return file_game_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ColumnScore) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *ColumnScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+var File_game_service_proto protoreflect.FileDescriptor
+
+const file_game_service_proto_rawDesc = "" +
+	"\n" +
+	"\x12game_service.proto\x12\rfourinarow.v1\x1a\x0eprotocol.proto\"p\n" +
+	"\x10FindMatchRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x14\n" +
+	"\x05guest\x18\x03 \x01(\bR\x05guest\x12\x14\n" +
+	"\x05queue\x18\x04 \x01(\tR\x05queue\"[\n" +
+	"\x11CreateGameRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x14\n" +
+	"\x05guest\x18\x03 \x01(\bR\x05guest\"r\n" +
+	"\x0fJoinGameRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12\x14\n" +
+	"\x05guest\x18\x04 \x01(\bR\x05guest\"n\n" +
+	"\vGameSession\x121\n" +
+	"\x06player\x18\x01 \x01(\v2\x19.fourinarow.v1.PlayerInfoR\x06player\x12,\n" +
+	"\x04game\x18\x02 \x01(\v2\x18.fourinarow.v1.GameStateR\x04game\"\x10\n" +
+	"\x0eGetGameRequest\"?\n" +
+	"\x10WatchGameRequest\x12\x1e\n" +
+	"\blast_seq\x18\x01 \x01(\x04H\x00R\alastSeq\x88\x01\x01B\v\n" +
+	"\t_last_seq\")\n" +
+	"\x0fMakeMoveRequest\x12\x16\n" +
+	"\x06column\x18\x01 \x01(\x05R\x06column\"\x0f\n" +
+	"\rResignRequest\".\n" +
+	"\x16AnalyzePositionRequest\x12\x14\n" +
+	"\x05moves\x18\x01 \x03(\x05R\x05moves\"\x87\x01\n" +
+	"\x17AnalyzePositionResponse\x12\x17\n" +
+	"\ato_move\x18\x01 \x01(\x05R\x06toMove\x12\x1f\n" +
+	"\vbest_column\x18\x02 \x01(\x05R\n" +
+	"bestColumn\x122\n" +
+	"\x06scores\x18\x03 \x03(\v2\x1a.fourinarow.v1.ColumnScoreR\x06scores\";\n" +
+	"\vColumnScore\x12\x16\n" +
+	"\x06column\x18\x01 \x01(\x05R\x06column\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score2\xe6\x04\n" +
+	"\vGameService\x12H\n" +
+	"\tFindMatch\x12\x1f.fourinarow.v1.FindMatchRequest\x1a\x1a.fourinarow.v1.GameSession\x12J\n" +
+	"\n" +
+	"CreateGame\x12 .fourinarow.v1.CreateGameRequest\x1a\x1a.fourinarow.v1.GameSession\x12F\n" +
+	"\bJoinGame\x12\x1e.fourinarow.v1.JoinGameRequest\x1a\x1a.fourinarow.v1.GameSession\x12B\n" +
+	"\aGetGame\x12\x1d.fourinarow.v1.GetGameRequest\x1a\x18.fourinarow.v1.GameState\x12L\n" +
+	"\tWatchGame\x12\x1f.fourinarow.v1.WatchGameRequest\x1a\x1c.fourinarow.v1.ServerMessage0\x01\x12C\n" +
+	"\bMakeMove\x12\x1e.fourinarow.v1.MakeMoveRequest\x1a\x17.fourinarow.v1.MoveMade\x12@\n" +
+	"\x06Resign\x12\x1c.fourinarow.v1.ResignRequest\x1a\x18.fourinarow.v1.GameState\x12`\n" +
+	"\x0fAnalyzePosition\x12%.fourinarow.v1.AnalyzePositionRequest\x1a&.fourinarow.v1.AnalyzePositionResponseB9Z7github.com/yourusername/4-in-a-row/internal/protocol/pbb\x06proto3"
+
+var (
+	file_game_service_proto_rawDescOnce sync.Once
+	file_game_service_proto_rawDescData []byte
+)
+
+func file_game_service_proto_rawDescGZIP() []byte {
+	file_game_service_proto_rawDescOnce.Do(func() {
+		file_game_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_game_service_proto_rawDesc), len(file_game_service_proto_rawDesc)))
+	})
+	return file_game_service_proto_rawDescData
+}
+
+var file_game_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_game_service_proto_goTypes = []any{
+	(*FindMatchRequest)(nil),        // 0: fourinarow.v1.FindMatchRequest
+	(*CreateGameRequest)(nil),       // 1: fourinarow.v1.CreateGameRequest
+	(*JoinGameRequest)(nil),         // 2: fourinarow.v1.JoinGameRequest
+	(*GameSession)(nil),             // 3: fourinarow.v1.GameSession
+	(*GetGameRequest)(nil),          // 4: fourinarow.v1.GetGameRequest
+	(*WatchGameRequest)(nil),        // 5: fourinarow.v1.WatchGameRequest
+	(*MakeMoveRequest)(nil),         // 6: fourinarow.v1.MakeMoveRequest
+	(*ResignRequest)(nil),           // 7: fourinarow.v1.ResignRequest
+	(*AnalyzePositionRequest)(nil),  // 8: fourinarow.v1.AnalyzePositionRequest
+	(*AnalyzePositionResponse)(nil), // 9: fourinarow.v1.AnalyzePositionResponse
+	(*ColumnScore)(nil),             // 10: fourinarow.v1.ColumnScore
+	(*PlayerInfo)(nil),              // 11: fourinarow.v1.PlayerInfo
+	(*GameState)(nil),               // 12: fourinarow.v1.GameState
+	(*ServerMessage)(nil),           // 13: fourinarow.v1.ServerMessage
+	(*MoveMade)(nil),                // 14: fourinarow.v1.MoveMade
+}
+var file_game_service_proto_depIdxs = []int32{
+	11, // 0: fourinarow.v1.GameSession.player:type_name -> fourinarow.v1.PlayerInfo
+	12, // 1: fourinarow.v1.GameSession.game:type_name -> fourinarow.v1.GameState
+	10, // 2: fourinarow.v1.AnalyzePositionResponse.scores:type_name -> fourinarow.v1.ColumnScore
+	0,  // 3: fourinarow.v1.GameService.FindMatch:input_type -> fourinarow.v1.FindMatchRequest
+	1,  // 4: fourinarow.v1.GameService.CreateGame:input_type -> fourinarow.v1.CreateGameRequest
+	2,  // 5: fourinarow.v1.GameService.JoinGame:input_type -> fourinarow.v1.JoinGameRequest
+	4,  // 6: fourinarow.v1.GameService.GetGame:input_type -> fourinarow.v1.GetGameRequest
+	5,  // 7: fourinarow.v1.GameService.WatchGame:input_type -> fourinarow.v1.WatchGameRequest
+	6,  // 8: fourinarow.v1.GameService.MakeMove:input_type -> fourinarow.v1.MakeMoveRequest
+	7,  // 9: fourinarow.v1.GameService.Resign:input_type -> fourinarow.v1.ResignRequest
+	8,  // 10: fourinarow.v1.GameService.AnalyzePosition:input_type -> fourinarow.v1.AnalyzePositionRequest
+	3,  // 11: fourinarow.v1.GameService.FindMatch:output_type -> fourinarow.v1.GameSession
+	3,  // 12: fourinarow.v1.GameService.CreateGame:output_type -> fourinarow.v1.GameSession
+	3,  // 13: fourinarow.v1.GameService.JoinGame:output_type -> fourinarow.v1.GameSession
+	12, // 14: fourinarow.v1.GameService.GetGame:output_type -> fourinarow.v1.GameState
+	13, // 15: fourinarow.v1.GameService.WatchGame:output_type -> fourinarow.v1.ServerMessage
+	14, // 16: fourinarow.v1.GameService.MakeMove:output_type -> fourinarow.v1.MoveMade
+	12, // 17: fourinarow.v1.GameService.Resign:output_type -> fourinarow.v1.GameState
+	9,  // 18: fourinarow.v1.GameService.AnalyzePosition:output_type -> fourinarow.v1.AnalyzePositionResponse
+	11, // [11:19] is the sub-list for method output_type
+	3,  // [3:11] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_game_service_proto_init() }
+func file_game_service_proto_init() {
+	if File_game_service_proto != nil {
+		return
+	}
+	file_protocol_proto_init()
+	file_game_service_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_game_service_proto_rawDesc), len(file_game_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_game_service_proto_goTypes,
+		DependencyIndexes: file_game_service_proto_depIdxs,
+		MessageInfos:      file_game_service_proto_msgTypes,
+	}.Build()
+	File_game_service_proto = out.File
+	file_game_service_proto_goTypes = nil
+	file_game_service_proto_depIdxs = nil
+}
@@ -0,0 +1,2612 @@
+// Binary encoding of the WebSocket protocol, spoken by clients that request
+// the "4inarow.v1.protobuf" subprotocol. Messages mirror the JSON protocol in
+// internal/protocol; each one travels in its own binary frame.
+//
+// Regenerate protocol.pb.go after editing:
+//   protoc --go_out=. --go_opt=paths=source_relative protocol.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: protocol.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ClientMessage is sent by clients; exactly one field of msg is set
+type ClientMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*ClientMessage_Hello
+	//	*ClientMessage_Join
+	//	*ClientMessage_Move
+	//	*ClientMessage_Reconnect
+	//	*ClientMessage_Heartbeat
+	//	*ClientMessage_Ack
+	//	*ClientMessage_Resync
+	//	*ClientMessage_CancelSearch
+	Msg           isClientMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientMessage) Reset() {
+	*x = ClientMessage{}
+	mi := &file_protocol_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientMessage) ProtoMessage() {}
+
+func (x *ClientMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientMessage.ProtoReflect.Descriptor instead.
+func (*ClientMessage) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientMessage) GetMsg() isClientMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetHello() *Hello {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetJoin() *Join {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Join); ok {
+			return x.Join
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetMove() *Move {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Move); ok {
+			return x.Move
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetReconnect() *Reconnect {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Reconnect); ok {
+			return x.Reconnect
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetAck() *Ack {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetResync() *Resync {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_Resync); ok {
+			return x.Resync
+		}
+	}
+	return nil
+}
+
+func (x *ClientMessage) GetCancelSearch() *CancelSearch {
+	if x != nil {
+		if x, ok := x.Msg.(*ClientMessage_CancelSearch); ok {
+			return x.CancelSearch
+		}
+	}
+	return nil
+}
+
+type isClientMessage_Msg interface {
+	isClientMessage_Msg()
+}
+
+type ClientMessage_Hello struct {
+	Hello *Hello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
+}
+
+type ClientMessage_Join struct {
+	Join *Join `protobuf:"bytes,2,opt,name=join,proto3,oneof"`
+}
+
+type ClientMessage_Move struct {
+	Move *Move `protobuf:"bytes,3,opt,name=move,proto3,oneof"`
+}
+
+type ClientMessage_Reconnect struct {
+	Reconnect *Reconnect `protobuf:"bytes,4,opt,name=reconnect,proto3,oneof"`
+}
+
+type ClientMessage_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,5,opt,name=heartbeat,proto3,oneof"`
+}
+
+type ClientMessage_Ack struct {
+	Ack *Ack `protobuf:"bytes,6,opt,name=ack,proto3,oneof"`
+}
+
+type ClientMessage_Resync struct {
+	Resync *Resync `protobuf:"bytes,7,opt,name=resync,proto3,oneof"`
+}
+
+type ClientMessage_CancelSearch struct {
+	CancelSearch *CancelSearch `protobuf:"bytes,8,opt,name=cancel_search,json=cancelSearch,proto3,oneof"`
+}
+
+func (*ClientMessage_Hello) isClientMessage_Msg() {}
+
+func (*ClientMessage_Join) isClientMessage_Msg() {}
+
+func (*ClientMessage_Move) isClientMessage_Msg() {}
+
+func (*ClientMessage_Reconnect) isClientMessage_Msg() {}
+
+func (*ClientMessage_Heartbeat) isClientMessage_Msg() {}
+
+func (*ClientMessage_Ack) isClientMessage_Msg() {}
+
+func (*ClientMessage_Resync) isClientMessage_Msg() {}
+
+func (*ClientMessage_CancelSearch) isClientMessage_Msg() {}
+
+type Hello struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []int32                `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hello) Reset() {
+	*x = Hello{}
+	mi := &file_protocol_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hello) ProtoMessage() {}
+
+func (x *Hello) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hello.ProtoReflect.Descriptor instead.
+func (*Hello) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Hello) GetVersions() []int32 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type Join struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Guest         bool                   `protobuf:"varint,3,opt,name=guest,proto3" json:"guest,omitempty"`
+	Queue         string                 `protobuf:"bytes,4,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Join) Reset() {
+	*x = Join{}
+	mi := &file_protocol_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Join) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Join) ProtoMessage() {}
+
+func (x *Join) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Join.ProtoReflect.Descriptor instead.
+func (*Join) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Join) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Join) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Join) GetGuest() bool {
+	if x != nil {
+		return x.Guest
+	}
+	return false
+}
+
+func (x *Join) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+type Move struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Column        *int32                 `protobuf:"varint,1,opt,name=column,proto3,oneof" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	mi := &file_protocol_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Move) GetColumn() int32 {
+	if x != nil && x.Column != nil {
+		return *x.Column
+	}
+	return 0
+}
+
+type Reconnect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionToken  string                 `protobuf:"bytes,1,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	LastSeq       *uint64                `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3,oneof" json:"last_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reconnect) Reset() {
+	*x = Reconnect{}
+	mi := &file_protocol_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reconnect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reconnect) ProtoMessage() {}
+
+func (x *Reconnect) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reconnect.ProtoReflect.Descriptor instead.
+func (*Reconnect) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Reconnect) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *Reconnect) GetLastSeq() uint64 {
+	if x != nil && x.LastSeq != nil {
+		return *x.LastSeq
+	}
+	return 0
+}
+
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_protocol_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{5}
+}
+
+type Ack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_protocol_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Ack) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type Resync struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromSeq       uint64                 `protobuf:"varint,1,opt,name=from_seq,json=fromSeq,proto3" json:"from_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resync) Reset() {
+	*x = Resync{}
+	mi := &file_protocol_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resync) ProtoMessage() {}
+
+func (x *Resync) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resync.ProtoReflect.Descriptor instead.
+func (*Resync) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Resync) GetFromSeq() uint64 {
+	if x != nil {
+		return x.FromSeq
+	}
+	return 0
+}
+
+type CancelSearch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSearch) Reset() {
+	*x = CancelSearch{}
+	mi := &file_protocol_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSearch) ProtoMessage() {}
+
+func (x *CancelSearch) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSearch.ProtoReflect.Descriptor instead.
+func (*CancelSearch) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{8}
+}
+
+// ServerMessage is sent by the server. seq is set on game events.
+type ServerMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Seq   uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*ServerMessage_Welcome
+	//	*ServerMessage_PlayerInfo
+	//	*ServerMessage_Waiting
+	//	*ServerMessage_Reconnected
+	//	*ServerMessage_SessionReplaced
+	//	*ServerMessage_HeartbeatAck
+	//	*ServerMessage_ServerDraining
+	//	*ServerMessage_Error
+	//	*ServerMessage_GameUpdate
+	//	*ServerMessage_GameDelta
+	//	*ServerMessage_GameStarted
+	//	*ServerMessage_MoveMade
+	//	*ServerMessage_TurnSkipped
+	//	*ServerMessage_GameOver
+	//	*ServerMessage_OpponentDisconnected
+	//	*ServerMessage_OpponentReconnected
+	//	*ServerMessage_QueueStatus
+	//	*ServerMessage_SearchCancelled
+	//	*ServerMessage_ArenaLeaderboard
+	Msg           isServerMessage_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerMessage) Reset() {
+	*x = ServerMessage{}
+	mi := &file_protocol_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerMessage) ProtoMessage() {}
+
+func (x *ServerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerMessage.ProtoReflect.Descriptor instead.
+func (*ServerMessage) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ServerMessage) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ServerMessage) GetMsg() isServerMessage_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetWelcome() *Welcome {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_Welcome); ok {
+			return x.Welcome
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetPlayerInfo() *PlayerInfo {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_PlayerInfo); ok {
+			return x.PlayerInfo
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetWaiting() *Waiting {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_Waiting); ok {
+			return x.Waiting
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetReconnected() *PlayerInfo {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_Reconnected); ok {
+			return x.Reconnected
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetSessionReplaced() *Notice {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_SessionReplaced); ok {
+			return x.SessionReplaced
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetHeartbeatAck() *HeartbeatAck {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_HeartbeatAck); ok {
+			return x.HeartbeatAck
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetServerDraining() *ServerDraining {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_ServerDraining); ok {
+			return x.ServerDraining
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetGameUpdate() *GameState {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_GameUpdate); ok {
+			return x.GameUpdate
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetGameDelta() *GameDelta {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_GameDelta); ok {
+			return x.GameDelta
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetGameStarted() *GameStarted {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_GameStarted); ok {
+			return x.GameStarted
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetMoveMade() *MoveMade {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_MoveMade); ok {
+			return x.MoveMade
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetTurnSkipped() *TurnSkipped {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_TurnSkipped); ok {
+			return x.TurnSkipped
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetGameOver() *GameOver {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_GameOver); ok {
+			return x.GameOver
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetOpponentDisconnected() *OpponentDisconnected {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_OpponentDisconnected); ok {
+			return x.OpponentDisconnected
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetOpponentReconnected() *OpponentReconnected {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_OpponentReconnected); ok {
+			return x.OpponentReconnected
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetQueueStatus() *QueueStatus {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_QueueStatus); ok {
+			return x.QueueStatus
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetSearchCancelled() *Notice {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_SearchCancelled); ok {
+			return x.SearchCancelled
+		}
+	}
+	return nil
+}
+
+func (x *ServerMessage) GetArenaLeaderboard() *ArenaLeaderboard {
+	if x != nil {
+		if x, ok := x.Msg.(*ServerMessage_ArenaLeaderboard); ok {
+			return x.ArenaLeaderboard
+		}
+	}
+	return nil
+}
+
+type isServerMessage_Msg interface {
+	isServerMessage_Msg()
+}
+
+type ServerMessage_Welcome struct {
+	Welcome *Welcome `protobuf:"bytes,2,opt,name=welcome,proto3,oneof"`
+}
+
+type ServerMessage_PlayerInfo struct {
+	PlayerInfo *PlayerInfo `protobuf:"bytes,3,opt,name=player_info,json=playerInfo,proto3,oneof"`
+}
+
+type ServerMessage_Waiting struct {
+	Waiting *Waiting `protobuf:"bytes,4,opt,name=waiting,proto3,oneof"`
+}
+
+type ServerMessage_Reconnected struct {
+	Reconnected *PlayerInfo `protobuf:"bytes,5,opt,name=reconnected,proto3,oneof"`
+}
+
+type ServerMessage_SessionReplaced struct {
+	SessionReplaced *Notice `protobuf:"bytes,6,opt,name=session_replaced,json=sessionReplaced,proto3,oneof"`
+}
+
+type ServerMessage_HeartbeatAck struct {
+	HeartbeatAck *HeartbeatAck `protobuf:"bytes,7,opt,name=heartbeat_ack,json=heartbeatAck,proto3,oneof"`
+}
+
+type ServerMessage_ServerDraining struct {
+	ServerDraining *ServerDraining `protobuf:"bytes,8,opt,name=server_draining,json=serverDraining,proto3,oneof"`
+}
+
+type ServerMessage_Error struct {
+	Error *Error `protobuf:"bytes,9,opt,name=error,proto3,oneof"`
+}
+
+type ServerMessage_GameUpdate struct {
+	GameUpdate *GameState `protobuf:"bytes,10,opt,name=game_update,json=gameUpdate,proto3,oneof"`
+}
+
+type ServerMessage_GameDelta struct {
+	GameDelta *GameDelta `protobuf:"bytes,11,opt,name=game_delta,json=gameDelta,proto3,oneof"`
+}
+
+type ServerMessage_GameStarted struct {
+	GameStarted *GameStarted `protobuf:"bytes,12,opt,name=game_started,json=gameStarted,proto3,oneof"`
+}
+
+type ServerMessage_MoveMade struct {
+	MoveMade *MoveMade `protobuf:"bytes,13,opt,name=move_made,json=moveMade,proto3,oneof"`
+}
+
+type ServerMessage_TurnSkipped struct {
+	TurnSkipped *TurnSkipped `protobuf:"bytes,14,opt,name=turn_skipped,json=turnSkipped,proto3,oneof"`
+}
+
+type ServerMessage_GameOver struct {
+	GameOver *GameOver `protobuf:"bytes,15,opt,name=game_over,json=gameOver,proto3,oneof"`
+}
+
+type ServerMessage_OpponentDisconnected struct {
+	OpponentDisconnected *OpponentDisconnected `protobuf:"bytes,16,opt,name=opponent_disconnected,json=opponentDisconnected,proto3,oneof"`
+}
+
+type ServerMessage_OpponentReconnected struct {
+	OpponentReconnected *OpponentReconnected `protobuf:"bytes,17,opt,name=opponent_reconnected,json=opponentReconnected,proto3,oneof"`
+}
+
+type ServerMessage_QueueStatus struct {
+	QueueStatus *QueueStatus `protobuf:"bytes,18,opt,name=queue_status,json=queueStatus,proto3,oneof"`
+}
+
+type ServerMessage_SearchCancelled struct {
+	SearchCancelled *Notice `protobuf:"bytes,19,opt,name=search_cancelled,json=searchCancelled,proto3,oneof"`
+}
+
+type ServerMessage_ArenaLeaderboard struct {
+	ArenaLeaderboard *ArenaLeaderboard `protobuf:"bytes,20,opt,name=arena_leaderboard,json=arenaLeaderboard,proto3,oneof"`
+}
+
+func (*ServerMessage_Welcome) isServerMessage_Msg() {}
+
+func (*ServerMessage_PlayerInfo) isServerMessage_Msg() {}
+
+func (*ServerMessage_Waiting) isServerMessage_Msg() {}
+
+func (*ServerMessage_Reconnected) isServerMessage_Msg() {}
+
+func (*ServerMessage_SessionReplaced) isServerMessage_Msg() {}
+
+func (*ServerMessage_HeartbeatAck) isServerMessage_Msg() {}
+
+func (*ServerMessage_ServerDraining) isServerMessage_Msg() {}
+
+func (*ServerMessage_Error) isServerMessage_Msg() {}
+
+func (*ServerMessage_GameUpdate) isServerMessage_Msg() {}
+
+func (*ServerMessage_GameDelta) isServerMessage_Msg() {}
+
+func (*ServerMessage_GameStarted) isServerMessage_Msg() {}
+
+func (*ServerMessage_MoveMade) isServerMessage_Msg() {}
+
+func (*ServerMessage_TurnSkipped) isServerMessage_Msg() {}
+
+func (*ServerMessage_GameOver) isServerMessage_Msg() {}
+
+func (*ServerMessage_OpponentDisconnected) isServerMessage_Msg() {}
+
+func (*ServerMessage_OpponentReconnected) isServerMessage_Msg() {}
+
+func (*ServerMessage_QueueStatus) isServerMessage_Msg() {}
+
+func (*ServerMessage_SearchCancelled) isServerMessage_Msg() {}
+
+func (*ServerMessage_ArenaLeaderboard) isServerMessage_Msg() {}
+
+type Welcome struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Version           int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	SupportedVersions []int32                `protobuf:"varint,2,rep,packed,name=supported_versions,json=supportedVersions,proto3" json:"supported_versions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Welcome) Reset() {
+	*x = Welcome{}
+	mi := &file_protocol_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Welcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Welcome) ProtoMessage() {}
+
+func (x *Welcome) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Welcome.ProtoReflect.Descriptor instead.
+func (*Welcome) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Welcome) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Welcome) GetSupportedVersions() []int32 {
+	if x != nil {
+		return x.SupportedVersions
+	}
+	return nil
+}
+
+type PlayerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	GameId        string                 `protobuf:"bytes,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	SessionToken  string                 `protobuf:"bytes,4,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerInfo) Reset() {
+	*x = PlayerInfo{}
+	mi := &file_protocol_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInfo) ProtoMessage() {}
+
+func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
+func (*PlayerInfo) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PlayerInfo) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+type Waiting struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Waiting) Reset() {
+	*x = Waiting{}
+	mi := &file_protocol_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Waiting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Waiting) ProtoMessage() {}
+
+func (x *Waiting) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Waiting.ProtoReflect.Descriptor instead.
+func (*Waiting) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Waiting) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// QueueStatus leaves bot_fallback_in_sec unset for queues without a bot
+// fallback
+type ArenaLeaderboard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ArenaId       string                 `protobuf:"bytes,1,opt,name=arena_id,json=arenaId,proto3" json:"arena_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	EndsAtUnixMs  int64                  `protobuf:"varint,4,opt,name=ends_at_unix_ms,json=endsAtUnixMs,proto3" json:"ends_at_unix_ms,omitempty"`
+	RemainingSec  int32                  `protobuf:"varint,5,opt,name=remaining_sec,json=remainingSec,proto3" json:"remaining_sec,omitempty"`
+	Standings     []*ArenaStanding       `protobuf:"bytes,6,rep,name=standings,proto3" json:"standings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArenaLeaderboard) Reset() {
+	*x = ArenaLeaderboard{}
+	mi := &file_protocol_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArenaLeaderboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArenaLeaderboard) ProtoMessage() {}
+
+func (x *ArenaLeaderboard) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArenaLeaderboard.ProtoReflect.Descriptor instead.
+func (*ArenaLeaderboard) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ArenaLeaderboard) GetArenaId() string {
+	if x != nil {
+		return x.ArenaId
+	}
+	return ""
+}
+
+func (x *ArenaLeaderboard) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ArenaLeaderboard) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ArenaLeaderboard) GetEndsAtUnixMs() int64 {
+	if x != nil {
+		return x.EndsAtUnixMs
+	}
+	return 0
+}
+
+func (x *ArenaLeaderboard) GetRemainingSec() int32 {
+	if x != nil {
+		return x.RemainingSec
+	}
+	return 0
+}
+
+func (x *ArenaLeaderboard) GetStandings() []*ArenaStanding {
+	if x != nil {
+		return x.Standings
+	}
+	return nil
+}
+
+type ArenaStanding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Score         int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Games         int32                  `protobuf:"varint,4,opt,name=games,proto3" json:"games,omitempty"`
+	Wins          int32                  `protobuf:"varint,5,opt,name=wins,proto3" json:"wins,omitempty"`
+	Draws         int32                  `protobuf:"varint,6,opt,name=draws,proto3" json:"draws,omitempty"`
+	Losses        int32                  `protobuf:"varint,7,opt,name=losses,proto3" json:"losses,omitempty"`
+	Streak        int32                  `protobuf:"varint,8,opt,name=streak,proto3" json:"streak,omitempty"`
+	OnFire        bool                   `protobuf:"varint,9,opt,name=on_fire,json=onFire,proto3" json:"on_fire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArenaStanding) Reset() {
+	*x = ArenaStanding{}
+	mi := &file_protocol_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArenaStanding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArenaStanding) ProtoMessage() {}
+
+func (x *ArenaStanding) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArenaStanding.ProtoReflect.Descriptor instead.
+func (*ArenaStanding) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ArenaStanding) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ArenaStanding) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetGames() int32 {
+	if x != nil {
+		return x.Games
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetDraws() int32 {
+	if x != nil {
+		return x.Draws
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetStreak() int32 {
+	if x != nil {
+		return x.Streak
+	}
+	return 0
+}
+
+func (x *ArenaStanding) GetOnFire() bool {
+	if x != nil {
+		return x.OnFire
+	}
+	return false
+}
+
+type QueueStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Queue            string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Position         int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	ElapsedSec       int32                  `protobuf:"varint,3,opt,name=elapsed_sec,json=elapsedSec,proto3" json:"elapsed_sec,omitempty"`
+	EstimatedWaitSec int32                  `protobuf:"varint,4,opt,name=estimated_wait_sec,json=estimatedWaitSec,proto3" json:"estimated_wait_sec,omitempty"`
+	BotFallbackInSec *int32                 `protobuf:"varint,5,opt,name=bot_fallback_in_sec,json=botFallbackInSec,proto3,oneof" json:"bot_fallback_in_sec,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QueueStatus) Reset() {
+	*x = QueueStatus{}
+	mi := &file_protocol_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatus) ProtoMessage() {}
+
+func (x *QueueStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatus.ProtoReflect.Descriptor instead.
+func (*QueueStatus) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QueueStatus) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *QueueStatus) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetElapsedSec() int32 {
+	if x != nil {
+		return x.ElapsedSec
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetEstimatedWaitSec() int32 {
+	if x != nil {
+		return x.EstimatedWaitSec
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetBotFallbackInSec() int32 {
+	if x != nil && x.BotFallbackInSec != nil {
+		return *x.BotFallbackInSec
+	}
+	return 0
+}
+
+type Notice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notice) Reset() {
+	*x = Notice{}
+	mi := &file_protocol_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notice) ProtoMessage() {}
+
+func (x *Notice) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notice.ProtoReflect.Descriptor instead.
+func (*Notice) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Notice) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type HeartbeatAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatAck) Reset() {
+	*x = HeartbeatAck{}
+	mi := &file_protocol_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatAck) ProtoMessage() {}
+
+func (x *HeartbeatAck) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatAck.ProtoReflect.Descriptor instead.
+func (*HeartbeatAck) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HeartbeatAck) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type ServerDraining struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Message        string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DeadlineUnixMs int64                  `protobuf:"varint,2,opt,name=deadline_unix_ms,json=deadlineUnixMs,proto3" json:"deadline_unix_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ServerDraining) Reset() {
+	*x = ServerDraining{}
+	mi := &file_protocol_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerDraining) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerDraining) ProtoMessage() {}
+
+func (x *ServerDraining) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerDraining.ProtoReflect.Descriptor instead.
+func (*ServerDraining) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ServerDraining) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ServerDraining) GetDeadlineUnixMs() int64 {
+	if x != nil {
+		return x.DeadlineUnixMs
+	}
+	return 0
+}
+
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_protocol_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type PlayerState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	IsBot         bool                   `protobuf:"varint,3,opt,name=is_bot,json=isBot,proto3" json:"is_bot,omitempty"`
+	Connected     bool                   `protobuf:"varint,4,opt,name=connected,proto3" json:"connected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerState) Reset() {
+	*x = PlayerState{}
+	mi := &file_protocol_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerState) ProtoMessage() {}
+
+func (x *PlayerState) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerState.ProtoReflect.Descriptor instead.
+func (*PlayerState) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PlayerState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PlayerState) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PlayerState) GetIsBot() bool {
+	if x != nil {
+		return x.IsBot
+	}
+	return false
+}
+
+func (x *PlayerState) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+// GameState is the full state of a game. The board holds Rows x Columns
+// cells row by row from the top, each 0 (empty), 1 or 2 (seat).
+type GameState struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Player1             *PlayerState           `protobuf:"bytes,2,opt,name=player1,proto3" json:"player1,omitempty"`
+	Player2             *PlayerState           `protobuf:"bytes,3,opt,name=player2,proto3" json:"player2,omitempty"`
+	Board               []byte                 `protobuf:"bytes,4,opt,name=board,proto3" json:"board,omitempty"`
+	CurrentTurn         int32                  `protobuf:"varint,5,opt,name=current_turn,json=currentTurn,proto3" json:"current_turn,omitempty"`
+	Status              string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Winner              *PlayerState           `protobuf:"bytes,7,opt,name=winner,proto3" json:"winner,omitempty"`
+	Result              string                 `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	CreatedAtUnixMs     int64                  `protobuf:"varint,9,opt,name=created_at_unix_ms,json=createdAtUnixMs,proto3" json:"created_at_unix_ms,omitempty"`
+	StartedAtUnixMs     int64                  `protobuf:"varint,10,opt,name=started_at_unix_ms,json=startedAtUnixMs,proto3" json:"started_at_unix_ms,omitempty"`
+	FinishedAtUnixMs    int64                  `protobuf:"varint,11,opt,name=finished_at_unix_ms,json=finishedAtUnixMs,proto3" json:"finished_at_unix_ms,omitempty"`
+	LastMoveAtUnixMs    int64                  `protobuf:"varint,12,opt,name=last_move_at_unix_ms,json=lastMoveAtUnixMs,proto3" json:"last_move_at_unix_ms,omitempty"`
+	TurnStartedAtUnixMs int64                  `protobuf:"varint,13,opt,name=turn_started_at_unix_ms,json=turnStartedAtUnixMs,proto3" json:"turn_started_at_unix_ms,omitempty"`
+	TurnTimeoutSec      int32                  `protobuf:"varint,14,opt,name=turn_timeout_sec,json=turnTimeoutSec,proto3" json:"turn_timeout_sec,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GameState) Reset() {
+	*x = GameState{}
+	mi := &file_protocol_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameState) ProtoMessage() {}
+
+func (x *GameState) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameState.ProtoReflect.Descriptor instead.
+func (*GameState) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GameState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GameState) GetPlayer1() *PlayerState {
+	if x != nil {
+		return x.Player1
+	}
+	return nil
+}
+
+func (x *GameState) GetPlayer2() *PlayerState {
+	if x != nil {
+		return x.Player2
+	}
+	return nil
+}
+
+func (x *GameState) GetBoard() []byte {
+	if x != nil {
+		return x.Board
+	}
+	return nil
+}
+
+func (x *GameState) GetCurrentTurn() int32 {
+	if x != nil {
+		return x.CurrentTurn
+	}
+	return 0
+}
+
+func (x *GameState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GameState) GetWinner() *PlayerState {
+	if x != nil {
+		return x.Winner
+	}
+	return nil
+}
+
+func (x *GameState) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *GameState) GetCreatedAtUnixMs() int64 {
+	if x != nil {
+		return x.CreatedAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameState) GetStartedAtUnixMs() int64 {
+	if x != nil {
+		return x.StartedAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameState) GetFinishedAtUnixMs() int64 {
+	if x != nil {
+		return x.FinishedAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameState) GetLastMoveAtUnixMs() int64 {
+	if x != nil {
+		return x.LastMoveAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameState) GetTurnStartedAtUnixMs() int64 {
+	if x != nil {
+		return x.TurnStartedAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameState) GetTurnTimeoutSec() int32 {
+	if x != nil {
+		return x.TurnTimeoutSec
+	}
+	return 0
+}
+
+// GameDelta replaces game_update when the client already holds the previous
+// state: it carries only the discs placed since then and the fields that
+// change on every turn
+type GameDelta struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Discs               []*Disc                `protobuf:"bytes,1,rep,name=discs,proto3" json:"discs,omitempty"`
+	CurrentTurn         int32                  `protobuf:"varint,2,opt,name=current_turn,json=currentTurn,proto3" json:"current_turn,omitempty"`
+	Status              string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	LastMoveAtUnixMs    int64                  `protobuf:"varint,4,opt,name=last_move_at_unix_ms,json=lastMoveAtUnixMs,proto3" json:"last_move_at_unix_ms,omitempty"`
+	TurnStartedAtUnixMs int64                  `protobuf:"varint,5,opt,name=turn_started_at_unix_ms,json=turnStartedAtUnixMs,proto3" json:"turn_started_at_unix_ms,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GameDelta) Reset() {
+	*x = GameDelta{}
+	mi := &file_protocol_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameDelta) ProtoMessage() {}
+
+func (x *GameDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameDelta.ProtoReflect.Descriptor instead.
+func (*GameDelta) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GameDelta) GetDiscs() []*Disc {
+	if x != nil {
+		return x.Discs
+	}
+	return nil
+}
+
+func (x *GameDelta) GetCurrentTurn() int32 {
+	if x != nil {
+		return x.CurrentTurn
+	}
+	return 0
+}
+
+func (x *GameDelta) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GameDelta) GetLastMoveAtUnixMs() int64 {
+	if x != nil {
+		return x.LastMoveAtUnixMs
+	}
+	return 0
+}
+
+func (x *GameDelta) GetTurnStartedAtUnixMs() int64 {
+	if x != nil {
+		return x.TurnStartedAtUnixMs
+	}
+	return 0
+}
+
+type Disc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Column        int32                  `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	Seat          int32                  `protobuf:"varint,3,opt,name=seat,proto3" json:"seat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Disc) Reset() {
+	*x = Disc{}
+	mi := &file_protocol_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Disc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Disc) ProtoMessage() {}
+
+func (x *Disc) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Disc.ProtoReflect.Descriptor instead.
+func (*Disc) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Disc) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *Disc) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Disc) GetSeat() int32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+type GameStarted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Player1       *PlayerState           `protobuf:"bytes,1,opt,name=player1,proto3" json:"player1,omitempty"`
+	Player2       *PlayerState           `protobuf:"bytes,2,opt,name=player2,proto3" json:"player2,omitempty"`
+	CurrentTurn   int32                  `protobuf:"varint,3,opt,name=current_turn,json=currentTurn,proto3" json:"current_turn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameStarted) Reset() {
+	*x = GameStarted{}
+	mi := &file_protocol_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameStarted) ProtoMessage() {}
+
+func (x *GameStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameStarted.ProtoReflect.Descriptor instead.
+func (*GameStarted) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GameStarted) GetPlayer1() *PlayerState {
+	if x != nil {
+		return x.Player1
+	}
+	return nil
+}
+
+func (x *GameStarted) GetPlayer2() *PlayerState {
+	if x != nil {
+		return x.Player2
+	}
+	return nil
+}
+
+func (x *GameStarted) GetCurrentTurn() int32 {
+	if x != nil {
+		return x.CurrentTurn
+	}
+	return 0
+}
+
+type MoveMade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MoveNumber    int32                  `protobuf:"varint,1,opt,name=move_number,json=moveNumber,proto3" json:"move_number,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Seat          int32                  `protobuf:"varint,3,opt,name=seat,proto3" json:"seat,omitempty"`
+	Column        int32                  `protobuf:"varint,4,opt,name=column,proto3" json:"column,omitempty"`
+	Row           int32                  `protobuf:"varint,5,opt,name=row,proto3" json:"row,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveMade) Reset() {
+	*x = MoveMade{}
+	mi := &file_protocol_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveMade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveMade) ProtoMessage() {}
+
+func (x *MoveMade) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveMade.ProtoReflect.Descriptor instead.
+func (*MoveMade) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MoveMade) GetMoveNumber() int32 {
+	if x != nil {
+		return x.MoveNumber
+	}
+	return 0
+}
+
+func (x *MoveMade) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *MoveMade) GetSeat() int32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+func (x *MoveMade) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *MoveMade) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+type TurnSkipped struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MoveNumber    int32                  `protobuf:"varint,1,opt,name=move_number,json=moveNumber,proto3" json:"move_number,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Seat          int32                  `protobuf:"varint,3,opt,name=seat,proto3" json:"seat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TurnSkipped) Reset() {
+	*x = TurnSkipped{}
+	mi := &file_protocol_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TurnSkipped) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TurnSkipped) ProtoMessage() {}
+
+func (x *TurnSkipped) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TurnSkipped.ProtoReflect.Descriptor instead.
+func (*TurnSkipped) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TurnSkipped) GetMoveNumber() int32 {
+	if x != nil {
+		return x.MoveNumber
+	}
+	return 0
+}
+
+func (x *TurnSkipped) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *TurnSkipped) GetSeat() int32 {
+	if x != nil {
+		return x.Seat
+	}
+	return 0
+}
+
+type GameOver struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Result        string                 `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	Winner        *PlayerState           `protobuf:"bytes,3,opt,name=winner,proto3" json:"winner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameOver) Reset() {
+	*x = GameOver{}
+	mi := &file_protocol_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameOver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameOver) ProtoMessage() {}
+
+func (x *GameOver) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameOver.ProtoReflect.Descriptor instead.
+func (*GameOver) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GameOver) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GameOver) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *GameOver) GetWinner() *PlayerState {
+	if x != nil {
+		return x.Winner
+	}
+	return nil
+}
+
+type OpponentDisconnected struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId            string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Username            string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	ForfeitAtUnixMs     int64                  `protobuf:"varint,3,opt,name=forfeit_at_unix_ms,json=forfeitAtUnixMs,proto3" json:"forfeit_at_unix_ms,omitempty"`
+	SecondsUntilForfeit int32                  `protobuf:"varint,4,opt,name=seconds_until_forfeit,json=secondsUntilForfeit,proto3" json:"seconds_until_forfeit,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *OpponentDisconnected) Reset() {
+	*x = OpponentDisconnected{}
+	mi := &file_protocol_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpponentDisconnected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpponentDisconnected) ProtoMessage() {}
+
+func (x *OpponentDisconnected) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpponentDisconnected.ProtoReflect.Descriptor instead.
+func (*OpponentDisconnected) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *OpponentDisconnected) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *OpponentDisconnected) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *OpponentDisconnected) GetForfeitAtUnixMs() int64 {
+	if x != nil {
+		return x.ForfeitAtUnixMs
+	}
+	return 0
+}
+
+func (x *OpponentDisconnected) GetSecondsUntilForfeit() int32 {
+	if x != nil {
+		return x.SecondsUntilForfeit
+	}
+	return 0
+}
+
+type OpponentReconnected struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpponentReconnected) Reset() {
+	*x = OpponentReconnected{}
+	mi := &file_protocol_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpponentReconnected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpponentReconnected) ProtoMessage() {}
+
+func (x *OpponentReconnected) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpponentReconnected.ProtoReflect.Descriptor instead.
+func (*OpponentReconnected) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *OpponentReconnected) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *OpponentReconnected) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+var File_protocol_proto protoreflect.FileDescriptor
+
+const file_protocol_proto_rawDesc = "" +
+	"\n" +
+	"\x0eprotocol.proto\x12\rfourinarow.v1\"\xab\x03\n" +
+	"\rClientMessage\x12,\n" +
+	"\x05hello\x18\x01 \x01(\v2\x14.fourinarow.v1.HelloH\x00R\x05hello\x12)\n" +
+	"\x04join\x18\x02 \x01(\v2\x13.fourinarow.v1.JoinH\x00R\x04join\x12)\n" +
+	"\x04move\x18\x03 \x01(\v2\x13.fourinarow.v1.MoveH\x00R\x04move\x128\n" +
+	"\treconnect\x18\x04 \x01(\v2\x18.fourinarow.v1.ReconnectH\x00R\treconnect\x128\n" +
+	"\theartbeat\x18\x05 \x01(\v2\x18.fourinarow.v1.HeartbeatH\x00R\theartbeat\x12&\n" +
+	"\x03ack\x18\x06 \x01(\v2\x12.fourinarow.v1.AckH\x00R\x03ack\x12/\n" +
+	"\x06resync\x18\a \x01(\v2\x15.fourinarow.v1.ResyncH\x00R\x06resync\x12B\n" +
+	"\rcancel_search\x18\b \x01(\v2\x1b.fourinarow.v1.CancelSearchH\x00R\fcancelSearchB\x05\n" +
+	"\x03msg\"#\n" +
+	"\x05Hello\x12\x1a\n" +
+	"\bversions\x18\x01 \x03(\x05R\bversions\"d\n" +
+	"\x04Join\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x14\n" +
+	"\x05guest\x18\x03 \x01(\bR\x05guest\x12\x14\n" +
+	"\x05queue\x18\x04 \x01(\tR\x05queue\".\n" +
+	"\x04Move\x12\x1b\n" +
+	"\x06column\x18\x01 \x01(\x05H\x00R\x06column\x88\x01\x01B\t\n" +
+	"\a_column\"]\n" +
+	"\tReconnect\x12#\n" +
+	"\rsession_token\x18\x01 \x01(\tR\fsessionToken\x12\x1e\n" +
+	"\blast_seq\x18\x02 \x01(\x04H\x00R\alastSeq\x88\x01\x01B\v\n" +
+	"\t_last_seq\"\v\n" +
+	"\tHeartbeat\"\x17\n" +
+	"\x03Ack\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\"#\n" +
+	"\x06Resync\x12\x19\n" +
+	"\bfrom_seq\x18\x01 \x01(\x04R\afromSeq\"\x0e\n" +
+	"\fCancelSearch\"\x81\n" +
+	"\n" +
+	"\rServerMessage\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x122\n" +
+	"\awelcome\x18\x02 \x01(\v2\x16.fourinarow.v1.WelcomeH\x00R\awelcome\x12<\n" +
+	"\vplayer_info\x18\x03 \x01(\v2\x19.fourinarow.v1.PlayerInfoH\x00R\n" +
+	"playerInfo\x122\n" +
+	"\awaiting\x18\x04 \x01(\v2\x16.fourinarow.v1.WaitingH\x00R\awaiting\x12=\n" +
+	"\vreconnected\x18\x05 \x01(\v2\x19.fourinarow.v1.PlayerInfoH\x00R\vreconnected\x12B\n" +
+	"\x10session_replaced\x18\x06 \x01(\v2\x15.fourinarow.v1.NoticeH\x00R\x0fsessionReplaced\x12B\n" +
+	"\rheartbeat_ack\x18\a \x01(\v2\x1b.fourinarow.v1.HeartbeatAckH\x00R\fheartbeatAck\x12H\n" +
+	"\x0fserver_draining\x18\b \x01(\v2\x1d.fourinarow.v1.ServerDrainingH\x00R\x0eserverDraining\x12,\n" +
+	"\x05error\x18\t \x01(\v2\x14.fourinarow.v1.ErrorH\x00R\x05error\x12;\n" +
+	"\vgame_update\x18\n" +
+	" \x01(\v2\x18.fourinarow.v1.GameStateH\x00R\n" +
+	"gameUpdate\x129\n" +
+	"\n" +
+	"game_delta\x18\v \x01(\v2\x18.fourinarow.v1.GameDeltaH\x00R\tgameDelta\x12?\n" +
+	"\fgame_started\x18\f \x01(\v2\x1a.fourinarow.v1.GameStartedH\x00R\vgameStarted\x126\n" +
+	"\tmove_made\x18\r \x01(\v2\x17.fourinarow.v1.MoveMadeH\x00R\bmoveMade\x12?\n" +
+	"\fturn_skipped\x18\x0e \x01(\v2\x1a.fourinarow.v1.TurnSkippedH\x00R\vturnSkipped\x126\n" +
+	"\tgame_over\x18\x0f \x01(\v2\x17.fourinarow.v1.GameOverH\x00R\bgameOver\x12Z\n" +
+	"\x15opponent_disconnected\x18\x10 \x01(\v2#.fourinarow.v1.OpponentDisconnectedH\x00R\x14opponentDisconnected\x12W\n" +
+	"\x14opponent_reconnected\x18\x11 \x01(\v2\".fourinarow.v1.OpponentReconnectedH\x00R\x13opponentReconnected\x12?\n" +
+	"\fqueue_status\x18\x12 \x01(\v2\x1a.fourinarow.v1.QueueStatusH\x00R\vqueueStatus\x12B\n" +
+	"\x10search_cancelled\x18\x13 \x01(\v2\x15.fourinarow.v1.NoticeH\x00R\x0fsearchCancelled\x12N\n" +
+	"\x11arena_leaderboard\x18\x14 \x01(\v2\x1f.fourinarow.v1.ArenaLeaderboardH\x00R\x10arenaLeaderboardB\x05\n" +
+	"\x03msg\"R\n" +
+	"\aWelcome\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12-\n" +
+	"\x12supported_versions\x18\x02 \x03(\x05R\x11supportedVersions\"\x83\x01\n" +
+	"\n" +
+	"PlayerInfo\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x17\n" +
+	"\agame_id\x18\x02 \x01(\tR\x06gameId\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12#\n" +
+	"\rsession_token\x18\x04 \x01(\tR\fsessionToken\"#\n" +
+	"\aWaiting\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xe1\x01\n" +
+	"\x10ArenaLeaderboard\x12\x19\n" +
+	"\barena_id\x18\x01 \x01(\tR\aarenaId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0fends_at_unix_ms\x18\x04 \x01(\x03R\fendsAtUnixMs\x12#\n" +
+	"\rremaining_sec\x18\x05 \x01(\x05R\fremainingSec\x12:\n" +
+	"\tstandings\x18\x06 \x03(\v2\x1c.fourinarow.v1.ArenaStandingR\tstandings\"\xde\x01\n" +
+	"\rArenaStanding\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\x12\x14\n" +
+	"\x05games\x18\x04 \x01(\x05R\x05games\x12\x12\n" +
+	"\x04wins\x18\x05 \x01(\x05R\x04wins\x12\x14\n" +
+	"\x05draws\x18\x06 \x01(\x05R\x05draws\x12\x16\n" +
+	"\x06losses\x18\a \x01(\x05R\x06losses\x12\x16\n" +
+	"\x06streak\x18\b \x01(\x05R\x06streak\x12\x17\n" +
+	"\aon_fire\x18\t \x01(\bR\x06onFire\"\xda\x01\n" +
+	"\vQueueStatus\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x12\x1f\n" +
+	"\velapsed_sec\x18\x03 \x01(\x05R\n" +
+	"elapsedSec\x12,\n" +
+	"\x12estimated_wait_sec\x18\x04 \x01(\x05R\x10estimatedWaitSec\x122\n" +
+	"\x13bot_fallback_in_sec\x18\x05 \x01(\x05H\x00R\x10botFallbackInSec\x88\x01\x01B\x16\n" +
+	"\x14_bot_fallback_in_sec\"\"\n" +
+	"\x06Notice\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\" \n" +
+	"\fHeartbeatAck\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\"T\n" +
+	"\x0eServerDraining\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12(\n" +
+	"\x10deadline_unix_ms\x18\x02 \x01(\x03R\x0edeadlineUnixMs\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"n\n" +
+	"\vPlayerState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x15\n" +
+	"\x06is_bot\x18\x03 \x01(\bR\x05isBot\x12\x1c\n" +
+	"\tconnected\x18\x04 \x01(\bR\tconnected\"\xbd\x04\n" +
+	"\tGameState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x124\n" +
+	"\aplayer1\x18\x02 \x01(\v2\x1a.fourinarow.v1.PlayerStateR\aplayer1\x124\n" +
+	"\aplayer2\x18\x03 \x01(\v2\x1a.fourinarow.v1.PlayerStateR\aplayer2\x12\x14\n" +
+	"\x05board\x18\x04 \x01(\fR\x05board\x12!\n" +
+	"\fcurrent_turn\x18\x05 \x01(\x05R\vcurrentTurn\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x122\n" +
+	"\x06winner\x18\a \x01(\v2\x1a.fourinarow.v1.PlayerStateR\x06winner\x12\x16\n" +
+	"\x06result\x18\b \x01(\tR\x06result\x12+\n" +
+	"\x12created_at_unix_ms\x18\t \x01(\x03R\x0fcreatedAtUnixMs\x12+\n" +
+	"\x12started_at_unix_ms\x18\n" +
+	" \x01(\x03R\x0fstartedAtUnixMs\x12-\n" +
+	"\x13finished_at_unix_ms\x18\v \x01(\x03R\x10finishedAtUnixMs\x12.\n" +
+	"\x14last_move_at_unix_ms\x18\f \x01(\x03R\x10lastMoveAtUnixMs\x124\n" +
+	"\x17turn_started_at_unix_ms\x18\r \x01(\x03R\x13turnStartedAtUnixMs\x12(\n" +
+	"\x10turn_timeout_sec\x18\x0e \x01(\x05R\x0eturnTimeoutSec\"\xd7\x01\n" +
+	"\tGameDelta\x12)\n" +
+	"\x05discs\x18\x01 \x03(\v2\x13.fourinarow.v1.DiscR\x05discs\x12!\n" +
+	"\fcurrent_turn\x18\x02 \x01(\x05R\vcurrentTurn\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12.\n" +
+	"\x14last_move_at_unix_ms\x18\x04 \x01(\x03R\x10lastMoveAtUnixMs\x124\n" +
+	"\x17turn_started_at_unix_ms\x18\x05 \x01(\x03R\x13turnStartedAtUnixMs\"D\n" +
+	"\x04Disc\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x16\n" +
+	"\x06column\x18\x02 \x01(\x05R\x06column\x12\x12\n" +
+	"\x04seat\x18\x03 \x01(\x05R\x04seat\"\x9c\x01\n" +
+	"\vGameStarted\x124\n" +
+	"\aplayer1\x18\x01 \x01(\v2\x1a.fourinarow.v1.PlayerStateR\aplayer1\x124\n" +
+	"\aplayer2\x18\x02 \x01(\v2\x1a.fourinarow.v1.PlayerStateR\aplayer2\x12!\n" +
+	"\fcurrent_turn\x18\x03 \x01(\x05R\vcurrentTurn\"\x86\x01\n" +
+	"\bMoveMade\x12\x1f\n" +
+	"\vmove_number\x18\x01 \x01(\x05R\n" +
+	"moveNumber\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04seat\x18\x03 \x01(\x05R\x04seat\x12\x16\n" +
+	"\x06column\x18\x04 \x01(\x05R\x06column\x12\x10\n" +
+	"\x03row\x18\x05 \x01(\x05R\x03row\"_\n" +
+	"\vTurnSkipped\x12\x1f\n" +
+	"\vmove_number\x18\x01 \x01(\x05R\n" +
+	"moveNumber\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04seat\x18\x03 \x01(\x05R\x04seat\"n\n" +
+	"\bGameOver\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
+	"\x06result\x18\x02 \x01(\tR\x06result\x122\n" +
+	"\x06winner\x18\x03 \x01(\v2\x1a.fourinarow.v1.PlayerStateR\x06winner\"\xb0\x01\n" +
+	"\x14OpponentDisconnected\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12+\n" +
+	"\x12forfeit_at_unix_ms\x18\x03 \x01(\x03R\x0fforfeitAtUnixMs\x122\n" +
+	"\x15seconds_until_forfeit\x18\x04 \x01(\x05R\x13secondsUntilForfeit\"N\n" +
+	"\x13OpponentReconnected\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busernameB9Z7github.com/yourusername/4-in-a-row/internal/protocol/pbb\x06proto3"
+
+var (
+	file_protocol_proto_rawDescOnce sync.Once
+	file_protocol_proto_rawDescData []byte
+)
+
+func file_protocol_proto_rawDescGZIP() []byte {
+	file_protocol_proto_rawDescOnce.Do(func() {
+		file_protocol_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protocol_proto_rawDesc), len(file_protocol_proto_rawDesc)))
+	})
+	return file_protocol_proto_rawDescData
+}
+
+var file_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_protocol_proto_goTypes = []any{
+	(*ClientMessage)(nil),        // 0: fourinarow.v1.ClientMessage
+	(*Hello)(nil),                // 1: fourinarow.v1.Hello
+	(*Join)(nil),                 // 2: fourinarow.v1.Join
+	(*Move)(nil),                 // 3: fourinarow.v1.Move
+	(*Reconnect)(nil),            // 4: fourinarow.v1.Reconnect
+	(*Heartbeat)(nil),            // 5: fourinarow.v1.Heartbeat
+	(*Ack)(nil),                  // 6: fourinarow.v1.Ack
+	(*Resync)(nil),               // 7: fourinarow.v1.Resync
+	(*CancelSearch)(nil),         // 8: fourinarow.v1.CancelSearch
+	(*ServerMessage)(nil),        // 9: fourinarow.v1.ServerMessage
+	(*Welcome)(nil),              // 10: fourinarow.v1.Welcome
+	(*PlayerInfo)(nil),           // 11: fourinarow.v1.PlayerInfo
+	(*Waiting)(nil),              // 12: fourinarow.v1.Waiting
+	(*ArenaLeaderboard)(nil),     // 13: fourinarow.v1.ArenaLeaderboard
+	(*ArenaStanding)(nil),        // 14: fourinarow.v1.ArenaStanding
+	(*QueueStatus)(nil),          // 15: fourinarow.v1.QueueStatus
+	(*Notice)(nil),               // 16: fourinarow.v1.Notice
+	(*HeartbeatAck)(nil),         // 17: fourinarow.v1.HeartbeatAck
+	(*ServerDraining)(nil),       // 18: fourinarow.v1.ServerDraining
+	(*Error)(nil),                // 19: fourinarow.v1.Error
+	(*PlayerState)(nil),          // 20: fourinarow.v1.PlayerState
+	(*GameState)(nil),            // 21: fourinarow.v1.GameState
+	(*GameDelta)(nil),            // 22: fourinarow.v1.GameDelta
+	(*Disc)(nil),                 // 23: fourinarow.v1.Disc
+	(*GameStarted)(nil),          // 24: fourinarow.v1.GameStarted
+	(*MoveMade)(nil),             // 25: fourinarow.v1.MoveMade
+	(*TurnSkipped)(nil),          // 26: fourinarow.v1.TurnSkipped
+	(*GameOver)(nil),             // 27: fourinarow.v1.GameOver
+	(*OpponentDisconnected)(nil), // 28: fourinarow.v1.OpponentDisconnected
+	(*OpponentReconnected)(nil),  // 29: fourinarow.v1.OpponentReconnected
+}
+var file_protocol_proto_depIdxs = []int32{
+	1,  // 0: fourinarow.v1.ClientMessage.hello:type_name -> fourinarow.v1.Hello
+	2,  // 1: fourinarow.v1.ClientMessage.join:type_name -> fourinarow.v1.Join
+	3,  // 2: fourinarow.v1.ClientMessage.move:type_name -> fourinarow.v1.Move
+	4,  // 3: fourinarow.v1.ClientMessage.reconnect:type_name -> fourinarow.v1.Reconnect
+	5,  // 4: fourinarow.v1.ClientMessage.heartbeat:type_name -> fourinarow.v1.Heartbeat
+	6,  // 5: fourinarow.v1.ClientMessage.ack:type_name -> fourinarow.v1.Ack
+	7,  // 6: fourinarow.v1.ClientMessage.resync:type_name -> fourinarow.v1.Resync
+	8,  // 7: fourinarow.v1.ClientMessage.cancel_search:type_name -> fourinarow.v1.CancelSearch
+	10, // 8: fourinarow.v1.ServerMessage.welcome:type_name -> fourinarow.v1.Welcome
+	11, // 9: fourinarow.v1.ServerMessage.player_info:type_name -> fourinarow.v1.PlayerInfo
+	12, // 10: fourinarow.v1.ServerMessage.waiting:type_name -> fourinarow.v1.Waiting
+	11, // 11: fourinarow.v1.ServerMessage.reconnected:type_name -> fourinarow.v1.PlayerInfo
+	16, // 12: fourinarow.v1.ServerMessage.session_replaced:type_name -> fourinarow.v1.Notice
+	17, // 13: fourinarow.v1.ServerMessage.heartbeat_ack:type_name -> fourinarow.v1.HeartbeatAck
+	18, // 14: fourinarow.v1.ServerMessage.server_draining:type_name -> fourinarow.v1.ServerDraining
+	19, // 15: fourinarow.v1.ServerMessage.error:type_name -> fourinarow.v1.Error
+	21, // 16: fourinarow.v1.ServerMessage.game_update:type_name -> fourinarow.v1.GameState
+	22, // 17: fourinarow.v1.ServerMessage.game_delta:type_name -> fourinarow.v1.GameDelta
+	24, // 18: fourinarow.v1.ServerMessage.game_started:type_name -> fourinarow.v1.GameStarted
+	25, // 19: fourinarow.v1.ServerMessage.move_made:type_name -> fourinarow.v1.MoveMade
+	26, // 20: fourinarow.v1.ServerMessage.turn_skipped:type_name -> fourinarow.v1.TurnSkipped
+	27, // 21: fourinarow.v1.ServerMessage.game_over:type_name -> fourinarow.v1.GameOver
+	28, // 22: fourinarow.v1.ServerMessage.opponent_disconnected:type_name -> fourinarow.v1.OpponentDisconnected
+	29, // 23: fourinarow.v1.ServerMessage.opponent_reconnected:type_name -> fourinarow.v1.OpponentReconnected
+	15, // 24: fourinarow.v1.ServerMessage.queue_status:type_name -> fourinarow.v1.QueueStatus
+	16, // 25: fourinarow.v1.ServerMessage.search_cancelled:type_name -> fourinarow.v1.Notice
+	13, // 26: fourinarow.v1.ServerMessage.arena_leaderboard:type_name -> fourinarow.v1.ArenaLeaderboard
+	14, // 27: fourinarow.v1.ArenaLeaderboard.standings:type_name -> fourinarow.v1.ArenaStanding
+	20, // 28: fourinarow.v1.GameState.player1:type_name -> fourinarow.v1.PlayerState
+	20, // 29: fourinarow.v1.GameState.player2:type_name -> fourinarow.v1.PlayerState
+	20, // 30: fourinarow.v1.GameState.winner:type_name -> fourinarow.v1.PlayerState
+	23, // 31: fourinarow.v1.GameDelta.discs:type_name -> fourinarow.v1.Disc
+	20, // 32: fourinarow.v1.GameStarted.player1:type_name -> fourinarow.v1.PlayerState
+	20, // 33: fourinarow.v1.GameStarted.player2:type_name -> fourinarow.v1.PlayerState
+	20, // 34: fourinarow.v1.GameOver.winner:type_name -> fourinarow.v1.PlayerState
+	35, // [35:35] is the sub-list for method output_type
+	35, // [35:35] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_protocol_proto_init() }
+func file_protocol_proto_init() {
+	if File_protocol_proto != nil {
+		return
+	}
+	file_protocol_proto_msgTypes[0].OneofWrappers = []any{
+		(*ClientMessage_Hello)(nil),
+		(*ClientMessage_Join)(nil),
+		(*ClientMessage_Move)(nil),
+		(*ClientMessage_Reconnect)(nil),
+		(*ClientMessage_Heartbeat)(nil),
+		(*ClientMessage_Ack)(nil),
+		(*ClientMessage_Resync)(nil),
+		(*ClientMessage_CancelSearch)(nil),
+	}
+	file_protocol_proto_msgTypes[3].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[4].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[9].OneofWrappers = []any{
+		(*ServerMessage_Welcome)(nil),
+		(*ServerMessage_PlayerInfo)(nil),
+		(*ServerMessage_Waiting)(nil),
+		(*ServerMessage_Reconnected)(nil),
+		(*ServerMessage_SessionReplaced)(nil),
+		(*ServerMessage_HeartbeatAck)(nil),
+		(*ServerMessage_ServerDraining)(nil),
+		(*ServerMessage_Error)(nil),
+		(*ServerMessage_GameUpdate)(nil),
+		(*ServerMessage_GameDelta)(nil),
+		(*ServerMessage_GameStarted)(nil),
+		(*ServerMessage_MoveMade)(nil),
+		(*ServerMessage_TurnSkipped)(nil),
+		(*ServerMessage_GameOver)(nil),
+		(*ServerMessage_OpponentDisconnected)(nil),
+		(*ServerMessage_OpponentReconnected)(nil),
+		(*ServerMessage_QueueStatus)(nil),
+		(*ServerMessage_SearchCancelled)(nil),
+		(*ServerMessage_ArenaLeaderboard)(nil),
+	}
+	file_protocol_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protocol_proto_rawDesc), len(file_protocol_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_protocol_proto_goTypes,
+		DependencyIndexes: file_protocol_proto_depIdxs,
+		MessageInfos:      file_protocol_proto_msgTypes,
+	}.Build()
+	File_protocol_proto = out.File
+	file_protocol_proto_goTypes = nil
+	file_protocol_proto_depIdxs = nil
+}
@@ -0,0 +1,115 @@
+// Package protocol defines every message exchanged with game clients over
+// WebSocket. Each message is an envelope whose type selects the payload;
+// payload types are listed in ClientMessages and ServerMessages, from which
+// the published JSON Schema is generated.
+package protocol
+
+import "encoding/json"
+
+//go:generate go run ../../cmd/protocol-schema -o schema.json
+
+// Version is the protocol version spoken by this server. Clients negotiate
+// it with a hello message; clients that skip the handshake get Version.
+const Version = 1
+
+// SupportedVersions lists every protocol version the server can speak
+var SupportedVersions = []int{1}
+
+// ClientEnvelope is a message sent by a client. The payload is decoded
+// according to Type, see DecodeClient.
+type ClientEnvelope struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// ServerEnvelope is a message sent by the server. Seq is set on events
+// scoped to a game and numbers them per game. Delta may accompany a
+// game_update and replaces it for protobuf clients; JSON clients always get
+// the full state.
+type ServerEnvelope struct {
+	Type    string      `json:"type"`
+	Seq     uint64      `json:"seq,omitempty"`
+	Payload interface{} `json:"payload"`
+	Delta   *GameDelta  `json:"-"`
+}
+
+// Client message types
+const (
+	TypeHello        = "hello"
+	TypeJoin         = "join"
+	TypeMove         = "move"
+	TypeReconnect    = "reconnect"
+	TypeHeartbeat    = "heartbeat"
+	TypeAck          = "ack"
+	TypeResync       = "resync"
+	TypeCancelSearch = "cancel_search"
+)
+
+// Server message types
+const (
+	TypeWelcome              = "welcome"
+	TypePlayerInfo           = "player_info"
+	TypeWaiting              = "waiting"
+	TypeQueueStatus          = "queue_status"
+	TypeSearchCancelled      = "search_cancelled"
+	TypeReconnected          = "reconnected"
+	TypeSessionReplaced      = "session_replaced"
+	TypeHeartbeatAck         = "heartbeat_ack"
+	TypeServerDraining       = "server_draining"
+	TypeError                = "error"
+	TypeGameUpdate           = "game_update"
+	TypeGameStarted          = "game_started"
+	TypeMoveMade             = "move_made"
+	TypeTurnSkipped          = "turn_skipped"
+	TypeGameOver             = "game_over"
+	TypeOpponentDisconnected = "opponent_disconnected"
+	TypeOpponentReconnected  = "opponent_reconnected"
+	TypeArenaLeaderboard     = "arena_leaderboard"
+)
+
+// ClientMessages maps each client message type to a constructor for its payload
+var ClientMessages = map[string]func() ClientPayload{
+	TypeHello:        func() ClientPayload { return &Hello{} },
+	TypeJoin:         func() ClientPayload { return &Join{} },
+	TypeMove:         func() ClientPayload { return &Move{} },
+	TypeReconnect:    func() ClientPayload { return &Reconnect{} },
+	TypeHeartbeat:    func() ClientPayload { return &Heartbeat{} },
+	TypeAck:          func() ClientPayload { return &Ack{} },
+	TypeResync:       func() ClientPayload { return &Resync{} },
+	TypeCancelSearch: func() ClientPayload { return &CancelSearch{} },
+}
+
+// ServerMessages maps each server message type to its payload type
+var ServerMessages = map[string]interface{}{
+	TypeWelcome:              Welcome{},
+	TypePlayerInfo:           PlayerInfo{},
+	TypeWaiting:              Waiting{},
+	TypeQueueStatus:          QueueStatus{},
+	TypeSearchCancelled:      SearchCancelled{},
+	TypeReconnected:          Reconnected{},
+	TypeSessionReplaced:      SessionReplaced{},
+	TypeHeartbeatAck:         HeartbeatAck{},
+	TypeServerDraining:       ServerDraining{},
+	TypeError:                Error{},
+	TypeGameUpdate:           GameState{},
+	TypeGameStarted:          GameStarted{},
+	TypeMoveMade:             MoveMade{},
+	TypeTurnSkipped:          TurnSkipped{},
+	TypeGameOver:             GameOver{},
+	TypeOpponentDisconnected: OpponentDisconnected{},
+	TypeOpponentReconnected:  OpponentReconnected{},
+	TypeArenaLeaderboard:     ArenaLeaderboard{},
+}
+
+// Negotiate picks the highest version supported by both sides. ok is false
+// when there is none.
+func Negotiate(clientVersions []int) (version int, ok bool) {
+	for _, v := range clientVersions {
+		for _, supported := range SupportedVersions {
+			if v == supported && v > version {
+				version = v
+			}
+		}
+	}
+	return version, version != 0
+}
@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaID identifies the published schema document
+const SchemaID = "https://4-in-a-row/protocol/v1/schema.json"
+
+// Schema returns the JSON Schema describing every message of the current
+// protocol version. It is generated from the Go types in this package, so it
+// cannot drift from what the server actually sends and accepts.
+func Schema() map[string]interface{} {
+	defs := map[string]interface{}{}
+	g := &schemaGenerator{defs: defs}
+
+	clientRefs := make([]interface{}, 0, len(ClientMessages))
+	for _, msgType := range sortedKeys(ClientMessages) {
+		payload := reflect.TypeOf(ClientMessages[msgType]()).Elem()
+		clientRefs = append(clientRefs, g.envelope(msgType, payload, false))
+	}
+
+	serverRefs := make([]interface{}, 0, len(ServerMessages))
+	for _, msgType := range sortedKeys(ServerMessages) {
+		payload := reflect.TypeOf(ServerMessages[msgType])
+		serverRefs = append(serverRefs, g.envelope(msgType, payload, true))
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   fmt.Sprintf("4 in a Row WebSocket protocol v%d", Version),
+		"version": Version,
+		"$defs":   defs,
+		"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/$defs/ClientMessage"},
+			map[string]interface{}{"$ref": "#/$defs/ServerMessage"},
+		},
+	}
+}
+
+// SchemaJSON returns Schema as indented JSON
+func SchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// envelope defines the message msgType carrying payload and returns a
+// reference to it
+func (g *schemaGenerator) envelope(msgType string, payload reflect.Type, server bool) map[string]interface{} {
+	properties := map[string]interface{}{
+		"type":    map[string]interface{}{"const": msgType},
+		"payload": g.typeSchema(payload),
+	}
+	required := []string{"type"}
+	if server {
+		properties["seq"] = map[string]interface{}{"type": "integer", "minimum": 1}
+		required = append(required, "payload")
+	}
+
+	name := exportedName(msgType) + "Message"
+	g.defs[name] = map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+
+	union := "ClientMessage"
+	if server {
+		union = "ServerMessage"
+	}
+	ref := map[string]interface{}{"$ref": "#/$defs/" + name}
+	if existing, ok := g.defs[union].(map[string]interface{}); ok {
+		existing["oneOf"] = append(existing["oneOf"].([]interface{}), ref)
+	} else {
+		g.defs[union] = map[string]interface{}{"oneOf": []interface{}{ref}}
+	}
+	return ref
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	errorCodeType = reflect.TypeOf(ErrorCode(""))
+)
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t == errorCodeType {
+		return map[string]interface{}{"type": "string", "enum": ErrorCodes()}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // reserve the name while recursing
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	}
+	panic(fmt.Sprintf("protocol: no schema for type %s", t))
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		prop := g.typeSchema(field.Type)
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop["enum"] = strings.Split(enum, ",")
+		}
+		if field.Tag.Get("nullable") == "true" {
+			prop = map[string]interface{}{"oneOf": []interface{}{prop, map[string]interface{}{"type": "null"}}}
+		}
+		properties[name] = prop
+
+		if opts != "omitempty" {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func exportedName(msgType string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(msgType, "_") {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
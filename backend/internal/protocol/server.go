@@ -0,0 +1,239 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/yourusername/4-in-a-row/internal/arena"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+// Welcome completes the handshake with the version the server will speak
+type Welcome struct {
+	Version           int   `json:"version"`
+	SupportedVersions []int `json:"supported_versions"`
+}
+
+// PlayerInfo tells a client which player and game it joined as. The session
+// token lets it reconnect later.
+type PlayerInfo struct {
+	PlayerID     string `json:"player_id"`
+	GameID       string `json:"game_id"`
+	Username     string `json:"username"`
+	SessionToken string `json:"session_token"`
+}
+
+// Reconnected confirms a reconnect, with the same fields as PlayerInfo
+type Reconnected PlayerInfo
+
+// Waiting tells a client it is queued for an opponent
+type Waiting struct {
+	Message string `json:"message"`
+}
+
+// QueueStatus tells a waiting client where it stands in its queue.
+// BotFallbackInSec is left out for queues that never fall back to a bot.
+type QueueStatus struct {
+	Queue            string `json:"queue"`
+	Position         int    `json:"position"`
+	ElapsedSec       int    `json:"elapsed_sec"`
+	EstimatedWaitSec int    `json:"estimated_wait_sec"`
+	BotFallbackInSec *int   `json:"bot_fallback_in_sec,omitempty"`
+}
+
+// SearchCancelled confirms a cancel_search; the client is back in the lobby
+type SearchCancelled struct {
+	Message string `json:"message"`
+}
+
+// SessionReplaced tells a client its game was resumed from another connection
+type SessionReplaced struct {
+	Message string `json:"message"`
+}
+
+// HeartbeatAck answers a heartbeat with the game's latest sequence number
+type HeartbeatAck struct {
+	Seq uint64 `json:"seq"`
+}
+
+// ServerDraining warns that the server is about to restart
+type ServerDraining struct {
+	Message  string    `json:"message"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// Error reports a request that failed. Clients should act on Code; Message
+// is meant for people and may change.
+type Error struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// PlayerState is a player as seen by other clients. Unlike game.Player it
+// never carries the session token.
+type PlayerState struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	IsBot     bool   `json:"is_bot"`
+	Connected bool   `json:"connected"`
+}
+
+// GameState is the full state of a game, sent as game_update
+type GameState struct {
+	ID             string       `json:"id"`
+	Player1        *PlayerState `json:"player1" nullable:"true"`
+	Player2        *PlayerState `json:"player2" nullable:"true"`
+	Board          [][]int      `json:"board"`
+	CurrentTurn    int          `json:"current_turn"`
+	Status         string       `json:"status" enum:"waiting,in_progress,finished,abandoned"`
+	Winner         *PlayerState `json:"winner,omitempty"`
+	Result         string       `json:"result,omitempty" enum:"player1_win,player2_win,draw,abandoned"`
+	CreatedAt      time.Time    `json:"created_at"`
+	StartedAt      *time.Time   `json:"started_at,omitempty"`
+	FinishedAt     *time.Time   `json:"finished_at,omitempty"`
+	LastMoveAt     time.Time    `json:"last_move_at"`
+	TurnStartedAt  time.Time    `json:"turn_started_at"`
+	TurnTimeoutSec int          `json:"turn_timeout_sec"`
+}
+
+// GameStarted announces that both seats are filled
+type GameStarted struct {
+	Player1     *PlayerState `json:"player1"`
+	Player2     *PlayerState `json:"player2"`
+	CurrentTurn int          `json:"current_turn"`
+}
+
+// MoveMade reports a disc dropped into the board
+type MoveMade struct {
+	MoveNumber int    `json:"move_number"`
+	PlayerID   string `json:"player_id"`
+	Seat       int    `json:"seat"`
+	Column     int    `json:"column"`
+	Row        int    `json:"row"`
+}
+
+// TurnSkipped reports a turn lost to the turn timer
+type TurnSkipped struct {
+	MoveNumber int    `json:"move_number"`
+	PlayerID   string `json:"player_id"`
+	Seat       int    `json:"seat"`
+}
+
+// GameOver reports how a game ended
+type GameOver struct {
+	Status string       `json:"status" enum:"finished,abandoned"`
+	Result string       `json:"result" enum:"player1_win,player2_win,draw,abandoned"`
+	Winner *PlayerState `json:"winner,omitempty"`
+}
+
+// OpponentDisconnected reports a player who dropped and when they forfeit
+type OpponentDisconnected struct {
+	PlayerID            string    `json:"player_id"`
+	Username            string    `json:"username"`
+	ForfeitAt           time.Time `json:"forfeit_at"`
+	SecondsUntilForfeit int       `json:"seconds_until_forfeit"`
+}
+
+// OpponentReconnected reports a player who came back
+type OpponentReconnected struct {
+	PlayerID string `json:"player_id"`
+	Username string `json:"username"`
+}
+
+// ArenaLeaderboard is the leaderboard of an arena, sent to its players when
+// it changes and when the arena ends
+type ArenaLeaderboard struct {
+	ArenaID      string          `json:"arena_id"`
+	Name         string          `json:"name"`
+	Status       string          `json:"status" enum:"scheduled,running,finished"`
+	EndsAt       time.Time       `json:"ends_at"`
+	RemainingSec int             `json:"remaining_sec"`
+	Standings    []ArenaStanding `json:"standings"`
+}
+
+// ArenaStanding is a player's place on an arena leaderboard. OnFire players
+// score double for their next game.
+type ArenaStanding struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Score    int    `json:"score"`
+	Games    int    `json:"games"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+	Streak   int    `json:"streak"`
+	OnFire   bool   `json:"on_fire"`
+}
+
+// NewPlayerState converts a player for publishing, or returns nil
+func NewPlayerState(p *game.Player) *PlayerState {
+	if p == nil {
+		return nil
+	}
+	return &PlayerState{
+		ID:        p.ID,
+		Username:  p.Username,
+		IsBot:     p.IsBot,
+		Connected: p.Connected,
+	}
+}
+
+// NewQueueStatus converts a waiting player's status for publishing
+func NewQueueStatus(status game.QueueStatus) QueueStatus {
+	payload := QueueStatus{
+		Queue:            status.Queue,
+		Position:         status.Position,
+		ElapsedSec:       int(status.Elapsed / time.Second),
+		EstimatedWaitSec: int(status.EstimatedWait.Round(time.Second) / time.Second),
+	}
+	if status.BotFallback != game.BotFallbackNone {
+		fallbackIn := int(status.BotFallbackIn.Round(time.Second) / time.Second)
+		payload.BotFallbackInSec = &fallbackIn
+	}
+	return payload
+}
+
+// NewArenaLeaderboard converts an arena's leaderboard for publishing
+func NewArenaLeaderboard(a *arena.Arena) ArenaLeaderboard {
+	standings := make([]ArenaStanding, len(a.Standings))
+	for i, s := range a.Standings {
+		standings[i] = ArenaStanding{
+			Rank:     s.Rank,
+			Username: s.Username,
+			Score:    s.Score,
+			Games:    s.Games,
+			Wins:     s.Wins,
+			Draws:    s.Draws,
+			Losses:   s.Losses,
+			Streak:   s.Streak,
+			OnFire:   s.OnFire,
+		}
+	}
+	return ArenaLeaderboard{
+		ArenaID:      a.ID,
+		Name:         a.Name,
+		Status:       a.Status,
+		EndsAt:       a.EndsAt,
+		RemainingSec: a.RemainingSec,
+		Standings:    standings,
+	}
+}
+
+// NewGameState converts a snapshot into the game_update payload
+func NewGameState(snap *game.Snapshot) GameState {
+	return GameState{
+		ID:             snap.ID,
+		Player1:        NewPlayerState(snap.Player1),
+		Player2:        NewPlayerState(snap.Player2),
+		Board:          snap.Board.ToArray(),
+		CurrentTurn:    int(snap.CurrentTurn),
+		Status:         string(snap.Status),
+		Winner:         NewPlayerState(snap.Winner),
+		Result:         string(snap.Result),
+		CreatedAt:      snap.CreatedAt,
+		StartedAt:      snap.StartedAt,
+		FinishedAt:     snap.FinishedAt,
+		LastMoveAt:     snap.LastMoveAt,
+		TurnStartedAt:  snap.TurnStartedAt,
+		TurnTimeoutSec: snap.TurnTimeoutSec,
+	}
+}
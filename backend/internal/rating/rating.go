@@ -0,0 +1,126 @@
+// Package rating implements the Glicko-2 rating system. Each rated game is
+// treated as its own rating period, so a player's rating moves after every
+// game rather than in batches.
+//
+// See Mark Glickman, "Example of the Glicko-2 system",
+// http://www.glicko.net/glicko/glicko2.pdf
+package rating
+
+import "math"
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// ProvisionalDeviation is the deviation above which a rating is still
+	// too uncertain to rank, as for players with only a few games
+	ProvisionalDeviation = 110.0
+
+	// MinDeviation keeps ratings of very active players responsive
+	MinDeviation = 45.0
+
+	tau     = 0.5      // constrains how fast volatility changes
+	scale   = 173.7178 // converts between the Glicko and Glicko-2 scales
+	epsilon = 0.000001 // convergence tolerance of the volatility iteration
+)
+
+// Scores of a game from one player's point of view
+const (
+	Loss = 0.0
+	Draw = 0.5
+	Win  = 1.0
+)
+
+// Rating is a player's Glicko-2 rating on the familiar Glicko scale
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Default returns the rating of a player who has not played yet
+func Default() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// Provisional reports whether the rating is still too uncertain to rank
+func (r Rating) Provisional() bool {
+	return r.Deviation > ProvisionalDeviation
+}
+
+// Expected returns the score r is expected to make against opponent, from 0
+// for a certain loss to 1 for a certain win
+func (r Rating) Expected(opponent Rating) float64 {
+	mu, muJ := toMu(r.Rating), toMu(opponent.Rating)
+	return expected(mu, muJ, g(opponent.Deviation/scale))
+}
+
+// Update returns r after a game against opponent in which r scored score:
+// Win, Draw or Loss
+func Update(r, opponent Rating, score float64) Rating {
+	mu, phi := toMu(r.Rating), r.Deviation/scale
+	muJ, phiJ := toMu(opponent.Rating), opponent.Deviation/scale
+
+	gJ := g(phiJ)
+	e := expected(mu, muJ, gJ)
+	v := 1 / (gJ * gJ * e * (1 - e))
+	delta := v * gJ * (score - e)
+
+	sigma := volatility(r.Volatility, phi, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*gJ*(score-e)
+
+	deviation := math.Min(math.Max(newPhi*scale, MinDeviation), DefaultDeviation)
+	return Rating{Rating: DefaultRating + newMu*scale, Deviation: deviation, Volatility: sigma}
+}
+
+func toMu(rating float64) float64 {
+	return (rating - DefaultRating) / scale
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
+
+// volatility finds the new volatility with the Illinois algorithm, step 5
+// of the Glicko-2 paper
+func volatility(sigma, phi, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,191 @@
+// Package season keeps the competitive seasons set in the configuration.
+// Each season has stats and ratings of its own, counted by the database as
+// games are saved, and once a season ends its final standings are archived.
+// The keeper also works out what a leaderboard covers: a season or all
+// time, over the whole of it or just the current week or month.
+package season
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/4-in-a-row/internal/clock"
+	"github.com/yourusername/4-in-a-row/internal/database"
+)
+
+// Current names whichever season is running now
+const Current = "current"
+
+// Period is the stretch of time a leaderboard covers
+type Period string
+
+// Leaderboard periods. Weeks start on Monday and, like months, at midnight
+// UTC.
+const (
+	PeriodAll     Period = "all"
+	PeriodWeekly  Period = "weekly"
+	PeriodMonthly Period = "monthly"
+)
+
+// ParsePeriod returns the period named s, which defaults to all
+func ParsePeriod(s string) (Period, bool) {
+	switch p := Period(s); p {
+	case "":
+		return PeriodAll, true
+	case PeriodAll, PeriodWeekly, PeriodMonthly:
+		return p, true
+	}
+	return "", false
+}
+
+// start returns when the period containing t started, or the zero time for
+// all
+func (p Period) start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case PeriodWeekly:
+		monday := t.Day() - (int(t.Weekday())+6)%7
+		return time.Date(t.Year(), t.Month(), monday, 0, 0, 0, 0, time.UTC)
+	case PeriodMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
+
+// Keeper saves the configured seasons and archives each once it has ended
+type Keeper struct {
+	db    *database.DB
+	clock clock.Clock
+
+	mu      sync.Mutex
+	seasons []database.Season // the earliest first
+}
+
+// NewKeeper creates a keeper for the configured seasons
+func NewKeeper(db *database.DB, clk clock.Clock, seasons []database.Season) *Keeper {
+	k := &Keeper{db: db, clock: clk}
+	for _, s := range seasons {
+		s.StartsAt, s.EndsAt = s.StartsAt.UTC(), s.EndsAt.UTC()
+		k.seasons = append(k.seasons, s)
+	}
+	return k
+}
+
+// Load saves the configured seasons and reads back every season known,
+// including those since dropped from the configuration
+func (k *Keeper) Load(ctx context.Context) error {
+	k.mu.Lock()
+	configured := k.seasons
+	k.mu.Unlock()
+
+	if err := k.db.SyncSeasons(ctx, configured); err != nil {
+		return err
+	}
+	seasons, err := k.db.ListSeasons(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list seasons: %w", err)
+	}
+
+	k.mu.Lock()
+	k.seasons = seasons
+	k.mu.Unlock()
+	return nil
+}
+
+// Run archives seasons as they end; it returns when ctx is cancelled
+func (k *Keeper) Run(ctx context.Context) {
+	ticker := k.clock.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	k.archive(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			k.archive(ctx)
+		}
+	}
+}
+
+// archive archives the final standings of every season that has ended
+func (k *Keeper) archive(ctx context.Context) {
+	now := k.clock.Now()
+	var ended []string
+	k.mu.Lock()
+	for _, s := range k.seasons {
+		if s.ArchivedAt == nil && !now.Before(s.EndsAt) {
+			ended = append(ended, s.Name)
+		}
+	}
+	k.mu.Unlock()
+
+	for _, name := range ended {
+		if err := k.db.ArchiveSeason(ctx, name); err != nil {
+			log.Printf("Failed to archive season %s: %v", name, err)
+			continue
+		}
+		log.Printf("Archived season %s", name)
+
+		k.mu.Lock()
+		for i := range k.seasons {
+			if k.seasons[i].Name == name {
+				archivedAt := now
+				k.seasons[i].ArchivedAt = &archivedAt
+			}
+		}
+		k.mu.Unlock()
+	}
+}
+
+// List returns every season, the earliest first
+func (k *Keeper) List() []database.Season {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]database.Season{}, k.seasons...)
+}
+
+// Find returns the season called name, or the one running now for Current
+func (k *Keeper) Find(name string) (database.Season, error) {
+	now := k.clock.Now()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, s := range k.seasons {
+		if s.Name == name || name == Current && !now.Before(s.StartsAt) && now.Before(s.EndsAt) {
+			return s, nil
+		}
+	}
+	return database.Season{}, database.ErrSeasonNotFound
+}
+
+// Scope returns what a leaderboard of season over period covers: all time
+// for an empty season, and the week or month so far for those periods. In
+// a season that has ended, those are its last week or month.
+func (k *Keeper) Scope(season string, period Period) (database.LeaderboardScope, error) {
+	now := k.clock.Now()
+	if season == "" {
+		return database.LeaderboardScope{From: period.start(now)}, nil
+	}
+
+	s, err := k.Find(season)
+	if err != nil {
+		return database.LeaderboardScope{}, err
+	}
+	scope := database.LeaderboardScope{Season: s.Name}
+	if period == PeriodAll {
+		return scope, nil
+	}
+
+	if !now.Before(s.EndsAt) {
+		now = s.EndsAt.Add(-time.Second)
+	}
+	scope.From, scope.To = period.start(now), s.EndsAt
+	if scope.From.Before(s.StartsAt) {
+		scope.From = s.StartsAt
+	}
+	return scope, nil
+}
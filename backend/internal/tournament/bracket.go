@@ -0,0 +1,307 @@
+package tournament
+
+import "github.com/yourusername/4-in-a-row/internal/database"
+
+// Sections of a knockout bracket
+const (
+	SectionWinners    = "winners"
+	SectionLosers     = "losers"      // double elimination only
+	SectionGrandFinal = "grand_final" // double elimination only
+)
+
+// Statuses of a bracket match
+const (
+	MatchPending    = "pending"     // waiting for its players
+	MatchInProgress = "in_progress" // being played
+	MatchFinished   = "finished"
+	MatchBye        = "bye"        // a player had no opponent and went through
+	MatchNotNeeded  = "not_needed" // the grand final reset, when the winners' champion won
+)
+
+// Bracket is the state of a knockout tournament: every match it can take,
+// the players they have been filled with so far and the games played in
+// them. It is rebuilt from the seeding and the games played whenever it is
+// needed, so only the games are stored.
+type Bracket struct {
+	Format   Format          `json:"format"`
+	BestOf   int             `json:"best_of"`
+	Matches  []*BracketMatch `json:"matches"` // by ID, each round's matches after those they are fed by
+	Champion string          `json:"champion,omitempty"`
+}
+
+// BracketMatch is a best-of-N match. Player1 and Player2 are empty until the
+// matches feeding them are decided, and stay empty for a bye.
+type BracketMatch struct {
+	ID       int                    `json:"id"`
+	Section  string                 `json:"section"`
+	Round    int                    `json:"round"`    // within its section, from 1
+	Position int                    `json:"position"` // within its round, from 1 at the top
+	Player1  string                 `json:"player1,omitempty"`
+	Player2  string                 `json:"player2,omitempty"`
+	Seed1    int                    `json:"seed1,omitempty"`
+	Seed2    int                    `json:"seed2,omitempty"`
+	Wins1    int                    `json:"wins1"`
+	Wins2    int                    `json:"wins2"`
+	Draws    int                    `json:"draws"`
+	Status   string                 `json:"status"`
+	Winner   string                 `json:"winner,omitempty"`
+	Games    []database.BracketGame `json:"games"`
+	WinnerTo *BracketSlot           `json:"winner_to,omitempty"`
+	LoserTo  *BracketSlot           `json:"loser_to,omitempty"` // double elimination only
+
+	sources [2]slotSource
+	reset   bool // the grand final reset
+	loser   string
+	decided bool // Winner and loser are final, either may be empty after byes
+}
+
+// BracketSlot is one of the two places of a match
+type BracketSlot struct {
+	Match int `json:"match"`
+	Slot  int `json:"slot"` // 1 or 2
+}
+
+// slotSource says who fills a match slot: a seed, or the winner or loser
+// of an earlier match
+type slotSource struct {
+	seed  int
+	match int
+	loser bool
+}
+
+// layoutBracket builds the matches of a bracket for players seeds, before
+// anyone has played. The bracket is sized for the next power of two, the
+// top seeds getting the byes, and seeded so the top two can only meet in
+// the final.
+func layoutBracket(format Format, players int) []*BracketMatch {
+	size, rounds := 2, 1
+	for size < players {
+		size *= 2
+		rounds++
+	}
+
+	var matches []*BracketMatch
+	add := func(section string, round, position int, a, b slotSource) *BracketMatch {
+		m := &BracketMatch{
+			ID:       len(matches) + 1,
+			Section:  section,
+			Round:    round,
+			Position: position,
+			sources:  [2]slotSource{a, b},
+		}
+		matches = append(matches, m)
+		return m
+	}
+	winnerOf := func(m *BracketMatch) slotSource { return slotSource{match: m.ID} }
+	loserOf := func(m *BracketMatch) slotSource { return slotSource{match: m.ID, loser: true} }
+
+	order := seedOrder(size)
+	winners := make([][]*BracketMatch, rounds)
+	for i := 0; i < size/2; i++ {
+		winners[0] = append(winners[0], add(SectionWinners, 1, i+1, slotSource{seed: order[2*i]}, slotSource{seed: order[2*i+1]}))
+	}
+	for r := 1; r < rounds; r++ {
+		prev := winners[r-1]
+		for i := 0; i < len(prev)/2; i++ {
+			winners[r] = append(winners[r], add(SectionWinners, r+1, i+1, winnerOf(prev[2*i]), winnerOf(prev[2*i+1])))
+		}
+	}
+	if format != FormatDoubleElimination {
+		return linkBracket(matches)
+	}
+
+	// The losers' bracket alternates rounds among its own players with
+	// rounds that take in the players just beaten in the winners' bracket.
+	// Those come in reversed every other round so that players do not
+	// meet again straight away.
+	final := winners[rounds-1][0]
+	champion := loserOf(final)
+	if rounds > 1 {
+		var losers []*BracketMatch
+		round := 1
+		for i := 0; i < len(winners[0])/2; i++ {
+			losers = append(losers, add(SectionLosers, round, i+1, loserOf(winners[0][2*i]), loserOf(winners[0][2*i+1])))
+		}
+		for r := 1; r < rounds; r++ {
+			round++
+			dropping := winners[r]
+			var next []*BracketMatch
+			for i := range losers {
+				from := dropping[i]
+				if r%2 == 1 {
+					from = dropping[len(dropping)-1-i]
+				}
+				next = append(next, add(SectionLosers, round, i+1, winnerOf(losers[i]), loserOf(from)))
+			}
+			losers = next
+
+			if r < rounds-1 {
+				round++
+				next = nil
+				for i := 0; i < len(losers)/2; i++ {
+					next = append(next, add(SectionLosers, round, i+1, winnerOf(losers[2*i]), winnerOf(losers[2*i+1])))
+				}
+				losers = next
+			}
+		}
+		champion = winnerOf(losers[0])
+	}
+
+	// The losers' champion has to beat the winners' champion twice
+	grandFinal := add(SectionGrandFinal, 1, 1, winnerOf(final), champion)
+	add(SectionGrandFinal, 2, 1, winnerOf(grandFinal), loserOf(grandFinal)).reset = true
+	return linkBracket(matches)
+}
+
+// linkBracket fills in where each match sends its winner and loser
+func linkBracket(matches []*BracketMatch) []*BracketMatch {
+	for _, m := range matches {
+		for slot, src := range m.sources {
+			if src.match == 0 {
+				continue
+			}
+			to := &BracketSlot{Match: m.ID, Slot: slot + 1}
+			if src.loser {
+				matches[src.match-1].LoserTo = to
+			} else {
+				matches[src.match-1].WinnerTo = to
+			}
+		}
+	}
+	return matches
+}
+
+// seedOrder lists the seeds of a bracket of size from top to bottom, so
+// that in the first round 1 meets size, 2 meets size-1 and so on, and the
+// better seed wins every match if the seeds all hold
+func seedOrder(size int) []int {
+	order := []int{1, 2}
+	for len(order) < size {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// buildBracket replays the games played so far on the bracket for seeded,
+// the players by seed
+func buildBracket(format Format, bestOf int, seeded []string, games []database.BracketGame) *Bracket {
+	b := &Bracket{
+		Format:  format,
+		BestOf:  bestOf,
+		Matches: layoutBracket(format, len(seeded)),
+	}
+	seeds := make(map[string]int, len(seeded))
+	for i, username := range seeded {
+		seeds[username] = i + 1
+	}
+	for _, g := range games {
+		if g.Match >= 1 && g.Match <= len(b.Matches) {
+			m := b.Matches[g.Match-1]
+			m.Games = append(m.Games, g)
+		}
+	}
+
+	// Matches only ever draw on earlier ones, so one pass settles them all
+	for _, m := range b.Matches {
+		var known [2]bool
+		players := [2]*string{&m.Player1, &m.Player2}
+		for i, src := range m.sources {
+			switch {
+			case src.seed > 0:
+				known[i] = true
+				if src.seed <= len(seeded) {
+					*players[i] = seeded[src.seed-1]
+				}
+			case b.Matches[src.match-1].decided:
+				known[i] = true
+				from := b.Matches[src.match-1]
+				if src.loser {
+					*players[i] = from.loser
+				} else {
+					*players[i] = from.Winner
+				}
+			}
+		}
+		m.Seed1, m.Seed2 = seeds[m.Player1], seeds[m.Player2]
+		if m.Games == nil {
+			m.Games = []database.BracketGame{}
+		}
+
+		// The reset is only played if the losers' champion, who came into
+		// the grand final in its second slot, won it
+		if m.reset {
+			grandFinal := b.Matches[m.sources[0].match-1]
+			if grandFinal.decided && grandFinal.Winner == grandFinal.Player1 {
+				m.Status = MatchNotNeeded
+				m.decide(grandFinal.Winner, grandFinal.loser)
+				continue
+			}
+		}
+		m.settle(known, bestOf)
+	}
+
+	last := b.Matches[len(b.Matches)-1]
+	if last.decided {
+		b.Champion = last.Winner
+	}
+	return b
+}
+
+// settle works out the state of a match from its players and games
+func (m *BracketMatch) settle(known [2]bool, bestOf int) {
+	switch {
+	case !known[0] || !known[1]:
+		m.Status = MatchPending
+		return
+	case m.Player1 == "" || m.Player2 == "":
+		m.Status = MatchBye
+		m.decide(m.Player1+m.Player2, "")
+		return
+	}
+
+	m.Status = MatchInProgress
+	needed := bestOf/2 + 1
+	for _, g := range m.Games {
+		winner := ""
+		switch g.Result {
+		case ResultPlayer1Win:
+			winner = g.Player1
+		case ResultPlayer2Win:
+			winner = g.Player2
+		case ResultDraw:
+			m.Draws++
+		case ResultDoubleForfeit:
+			// Neither player turned up: the better seed goes through
+			if m.Seed1 < m.Seed2 {
+				m.decide(m.Player1, m.Player2)
+			} else {
+				m.decide(m.Player2, m.Player1)
+			}
+			m.Status = MatchFinished
+			return
+		}
+		switch winner {
+		case m.Player1:
+			m.Wins1++
+		case m.Player2:
+			m.Wins2++
+		}
+	}
+
+	switch {
+	case m.Wins1 >= needed:
+		m.decide(m.Player1, m.Player2)
+		m.Status = MatchFinished
+	case m.Wins2 >= needed:
+		m.decide(m.Player2, m.Player1)
+		m.Status = MatchFinished
+	}
+}
+
+func (m *BracketMatch) decide(winner, loser string) {
+	m.Winner, m.loser, m.decided = winner, loser, true
+}
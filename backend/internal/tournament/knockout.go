@@ -0,0 +1,125 @@
+package tournament
+
+import (
+	"context"
+	"log"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+// Knockouts have no rounds to wait for: each match plays its games as soon
+// as both its players are known, and every finished game may decide a
+// match and fill a slot further down the bracket.
+
+// bracket rebuilds the bracket of a knockout from its seeding and games
+func (t *Tournament) bracket() *Bracket {
+	seeded := make([]string, len(t.Players))
+	for i, p := range t.Players {
+		seeded[i] = p.Username
+	}
+	return buildBracket(Format(t.Format), t.BestOf, seeded, t.BracketGames)
+}
+
+// nextGame returns the game a match should play next, if it is being
+// played and is not waiting for a game to finish. Players take turns to
+// move first, the first slot starting.
+func (m *BracketMatch) nextGame() (database.BracketGame, bool) {
+	if m.Status != MatchInProgress {
+		return database.BracketGame{}, false
+	}
+	if n := len(m.Games); n > 0 && m.Games[n-1].Result == ResultPending {
+		return database.BracketGame{}, false
+	}
+
+	g := database.BracketGame{Match: m.ID, Game: len(m.Games) + 1, Player1: m.Player1, Player2: m.Player2}
+	if g.Game%2 == 0 {
+		g.Player1, g.Player2 = g.Player2, g.Player1
+	}
+	return g, true
+}
+
+// advanceBracketLocked starts the next game of every match that is ready
+// for one and saves them, or ends the tournament once its bracket has a
+// champion
+func (d *Director) advanceBracketLocked(ctx context.Context, t *Tournament) error {
+	var started []database.BracketGame
+	for {
+		b := t.bracket()
+		if b.Champion != "" {
+			d.finishLocked(ctx, t, started...)
+			log.Printf("Tournament %s won by %s", t.ID, b.Champion)
+			return nil
+		}
+
+		var next []database.BracketGame
+		for _, m := range b.Matches {
+			if g, ok := m.nextGame(); ok {
+				next = append(next, g)
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+
+		// A game that cannot be created is decided on the spot, which may
+		// decide its match, so look again until every game is under way
+		failed := false
+		for i := range next {
+			g := &next[i]
+			g.GameID, g.Result = d.startGameLocked(t, g.Player1, g.Player2)
+			failed = failed || g.Result != ResultPending
+		}
+		t.BracketGames = append(t.BracketGames, next...)
+		started = append(started, next...)
+		if !failed {
+			break
+		}
+	}
+
+	if len(started) > 0 {
+		log.Printf("Tournament %s started %d bracket games", t.ID, len(started))
+	}
+	return d.db.SaveTournamentProgress(ctx, &t.Tournament, nil, started)
+}
+
+// recordBracketGameLocked records the result of a knockout game and moves
+// the bracket on
+func (d *Director) recordBracketGameLocked(t *Tournament, snap *game.Snapshot) {
+	var played *database.BracketGame
+	for i := range t.BracketGames {
+		if t.BracketGames[i].GameID == snap.ID {
+			played = &t.BracketGames[i]
+		}
+	}
+	if played == nil || played.Result != ResultPending {
+		return
+	}
+	played.Result = gameResult(played.Player1, played.Player2, snap)
+	log.Printf("Tournament %s match %d game %d: %s", t.ID, played.Match, played.Game, played.Result)
+
+	ctx := context.Background()
+	if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, nil, []database.BracketGame{*played}); err != nil {
+		log.Printf("Failed to save tournament %s result: %v", t.ID, err)
+	}
+	if err := d.advanceBracketLocked(ctx, t); err != nil {
+		log.Printf("Failed to advance tournament %s: %v", t.ID, err)
+	}
+}
+
+// resumeBracketLocked starts the unfinished games of a knockout again after
+// a restart, and any games that were due but never started
+func (d *Director) resumeBracketLocked(ctx context.Context, t *Tournament) error {
+	var restarted []database.BracketGame
+	for i := range t.BracketGames {
+		g := &t.BracketGames[i]
+		if g.Result == ResultPending {
+			g.GameID, g.Result = d.startGameLocked(t, g.Player1, g.Player2)
+			restarted = append(restarted, *g)
+		}
+	}
+	if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, nil, restarted); err != nil {
+		return err
+	}
+	return d.advanceBracketLocked(ctx, t)
+}
@@ -0,0 +1,199 @@
+package tournament
+
+import "github.com/yourusername/4-in-a-row/internal/database"
+
+// maxPairingSteps bounds the search for a Swiss round without rematches;
+// past it the round is paired in ranking order, rematches and all
+const maxPairingSteps = 100000
+
+// swissRounds is the default number of Swiss rounds for n players: enough
+// for a single player to be left with a perfect score
+func swissRounds(n int) int {
+	rounds := 1
+	for 1<<rounds < n {
+		rounds++
+	}
+	return rounds
+}
+
+// roundRobinRounds is the number of rounds in which n players each meet
+// every other player once
+func roundRobinRounds(n int) int {
+	if n%2 == 1 {
+		return n
+	}
+	return n - 1
+}
+
+// swissPairings pairs a Swiss round from the current standings. With an odd
+// number of players the lowest ranked player who has not had a bye yet sits
+// out. Everyone else is paired within their score group, its top half
+// against its bottom half, backtracking to avoid rematches and floating
+// players down to the next group when their own runs out.
+func swissPairings(round int, standings []Standing, history []database.TournamentPairing) []database.TournamentPairing {
+	played := make(map[[2]string]bool)
+	byes := make(map[string]bool)
+	firsts := make(map[string]int) // games each player moved first in
+	for _, p := range history {
+		if p.Result == ResultBye {
+			byes[p.Player1] = true
+			continue
+		}
+		played[pairKey(p.Player1, p.Player2)] = true
+		firsts[p.Player1]++
+	}
+
+	points := make(map[string]float64, len(standings))
+	players := make([]string, len(standings))
+	for i, s := range standings {
+		points[s.Username] = s.Points
+		players[i] = s.Username
+	}
+
+	bye := ""
+	if len(players)%2 == 1 {
+		i := len(players) - 1
+		for j := len(players) - 1; j >= 0; j-- {
+			if !byes[players[j]] {
+				i = j
+				break
+			}
+		}
+		bye = players[i]
+		players = append(players[:i], players[i+1:]...)
+	}
+
+	steps := 0
+	pairs, ok := pairWithoutRematches(players, points, played, &steps)
+	if !ok {
+		pairs = nil
+		for i := 0; i+1 < len(players); i += 2 {
+			pairs = append(pairs, [2]string{players[i], players[i+1]})
+		}
+	}
+
+	pairings := make([]database.TournamentPairing, 0, len(pairs)+1)
+	for i, pair := range pairs {
+		// Moving first is an advantage, so give it to whoever has had it
+		// less often, the higher ranked player on a tie
+		first, second := pair[0], pair[1]
+		if firsts[second] < firsts[first] {
+			first, second = second, first
+		}
+		pairings = append(pairings, database.TournamentPairing{Round: round, Board: i + 1, Player1: first, Player2: second})
+	}
+	if bye != "" {
+		pairings = append(pairings, database.TournamentPairing{Round: round, Board: len(pairs) + 1, Player1: bye, Result: ResultBye})
+	}
+	return pairings
+}
+
+// pairWithoutRematches pairs players, in ranking order, so that nobody
+// meets an opponent again. ok is false if that is impossible or takes more
+// than maxPairingSteps.
+func pairWithoutRematches(players []string, points map[string]float64, played map[[2]string]bool, steps *int) (pairs [][2]string, ok bool) {
+	if len(players) == 0 {
+		return nil, true
+	}
+
+	first := players[0]
+	for _, i := range candidates(players, points) {
+		*steps++
+		if *steps > maxPairingSteps {
+			return nil, false
+		}
+		if played[pairKey(first, players[i])] {
+			continue
+		}
+
+		rest := make([]string, 0, len(players)-2)
+		rest = append(rest, players[1:i]...)
+		rest = append(rest, players[i+1:]...)
+		if restPairs, ok := pairWithoutRematches(rest, points, played, steps); ok {
+			return append([][2]string{{first, players[i]}}, restPairs...), true
+		}
+	}
+	return nil, false
+}
+
+// candidates returns the indexes of the opponents players[0] should meet,
+// best first: the player half a score group below them, then the rest of
+// the bottom half, the top half, and finally the lower score groups
+func candidates(players []string, points map[string]float64) []int {
+	group := 1
+	for group < len(players) && points[players[group]] == points[players[0]] {
+		group++
+	}
+
+	order := make([]int, 0, len(players)-1)
+	half := max(group/2, 1)
+	for i := half; i < group; i++ {
+		order = append(order, i)
+	}
+	for i := 1; i < half; i++ {
+		order = append(order, i)
+	}
+	for i := group; i < len(players); i++ {
+		order = append(order, i)
+	}
+	return order
+}
+
+// roundRobinPairings pairs a round-robin round with the circle method:
+// seeded stays in place for the first seed while everyone else rotates one
+// seat per round. With an odd number of players, whoever meets the empty
+// seat has a bye.
+func roundRobinPairings(round int, seeded []string) []database.TournamentPairing {
+	seeds := make(map[string]int, len(seeded))
+	for i, username := range seeded {
+		seeds[username] = i
+	}
+
+	seats := append([]string(nil), seeded...)
+	if len(seats)%2 == 1 {
+		seats = append(seats, "")
+	}
+	n := len(seats)
+
+	rotated := make([]string, n)
+	rotated[0] = seats[0]
+	for i := 1; i < n; i++ {
+		rotated[i] = seats[1+((i-1)+(round-1))%(n-1)]
+	}
+
+	var pairings, byes []database.TournamentPairing
+	for i := 0; i < n/2; i++ {
+		first, second := rotated[i], rotated[n-1-i]
+		switch {
+		case first == "":
+			byes = append(byes, database.TournamentPairing{Round: round, Player1: second, Result: ResultBye})
+		case second == "":
+			byes = append(byes, database.TournamentPairing{Round: round, Player1: first, Result: ResultBye})
+		default:
+			// Every pair meets once, so who moves first only depends on
+			// the pair: the higher seed when their seeds add up to an odd
+			// number. That gives everyone the first move about half the time.
+			if seeds[first] > seeds[second] {
+				first, second = second, first
+			}
+			if (seeds[first]+seeds[second])%2 == 0 {
+				first, second = second, first
+			}
+			pairings = append(pairings, database.TournamentPairing{Round: round, Player1: first, Player2: second})
+		}
+	}
+
+	pairings = append(pairings, byes...)
+	for i := range pairings {
+		pairings[i].Board = i + 1
+	}
+	return pairings
+}
+
+// pairKey identifies a pair of usernames regardless of order
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
@@ -0,0 +1,142 @@
+package tournament
+
+import (
+	"sort"
+
+	"github.com/yourusername/4-in-a-row/internal/database"
+)
+
+// Results of a pairing, from the point of view of its first player
+const (
+	ResultPending       = ""
+	ResultPlayer1Win    = "player1_win"
+	ResultPlayer2Win    = "player2_win"
+	ResultDraw          = "draw"
+	ResultBye           = "bye"            // Player1 sat the round out and scores a win
+	ResultDoubleForfeit = "double_forfeit" // the game ended without a winner, neither scores
+)
+
+// Standing is a player's score in a tournament. Ties on points are broken
+// by Buchholz, the sum of the opponents' points, then by Sonneborn-Berger,
+// the points of the opponents beaten plus half those of the opponents drawn.
+type Standing struct {
+	Rank            int     `json:"rank"` // shared by players tied on points and both tie-breaks
+	Username        string  `json:"username"`
+	Points          float64 `json:"points"`
+	Buchholz        float64 `json:"buchholz"`
+	SonnebornBerger float64 `json:"sonneborn_berger"`
+	Played          int     `json:"played"`
+	Wins            int     `json:"wins"`
+	Draws           int     `json:"draws"`
+	Losses          int     `json:"losses"`
+	Byes            int     `json:"byes"`
+
+	rating    int
+	opponents []opponentScore
+}
+
+// opponentScore is what a player scored against one opponent
+type opponentScore struct {
+	username string
+	score    float64
+}
+
+// scores returns what each player of a decided pairing scored; ok is false
+// while the game is still being played
+func scores(result string) (score1, score2 float64, ok bool) {
+	switch result {
+	case ResultPlayer1Win, ResultBye:
+		return 1, 0, true
+	case ResultPlayer2Win:
+		return 0, 1, true
+	case ResultDraw:
+		return 0.5, 0.5, true
+	case ResultDoubleForfeit:
+		return 0, 0, true
+	}
+	return 0, 0, false
+}
+
+// computeStandings ranks players by the decided pairings so far. Players
+// still level after both tie-breaks are listed by seeding rating.
+func computeStandings(players []database.TournamentPlayer, pairings []database.TournamentPairing) []Standing {
+	standings := make([]Standing, len(players))
+	byName := make(map[string]*Standing, len(players))
+	for i, p := range players {
+		standings[i] = Standing{Username: p.Username, rating: p.Rating}
+		byName[p.Username] = &standings[i]
+	}
+
+	for _, p := range pairings {
+		score1, score2, ok := scores(p.Result)
+		s1 := byName[p.Player1]
+		if !ok || s1 == nil {
+			continue
+		}
+		if p.Result == ResultBye {
+			s1.Points += score1
+			s1.Byes++
+			continue
+		}
+		s2 := byName[p.Player2]
+		if s2 == nil {
+			continue
+		}
+		s1.record(p.Player2, score1)
+		s2.record(p.Player1, score2)
+	}
+
+	for i := range standings {
+		s := &standings[i]
+		for _, opp := range s.opponents {
+			points := byName[opp.username].Points
+			s.Buchholz += points
+			s.SonnebornBerger += opp.score * points
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if !a.tiedWith(b) {
+			if a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if a.Buchholz != b.Buchholz {
+				return a.Buchholz > b.Buchholz
+			}
+			return a.SonnebornBerger > b.SonnebornBerger
+		}
+		if a.rating != b.rating {
+			return a.rating > b.rating
+		}
+		return a.Username < b.Username
+	})
+	for i := range standings {
+		if i > 0 && standings[i].tiedWith(standings[i-1]) {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+	return standings
+}
+
+// record adds a game against opponent in which s scored score
+func (s *Standing) record(opponent string, score float64) {
+	s.Points += score
+	s.Played++
+	switch score {
+	case 1:
+		s.Wins++
+	case 0.5:
+		s.Draws++
+	default:
+		s.Losses++
+	}
+	s.opponents = append(s.opponents, opponentScore{username: opponent, score: score})
+}
+
+// tiedWith reports whether s and other cannot be separated by the tie-breaks
+func (s Standing) tiedWith(other Standing) bool {
+	return s.Points == other.Points && s.Buchholz == other.Buchholz && s.SonnebornBerger == other.SonnebornBerger
+}
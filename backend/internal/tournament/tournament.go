@@ -0,0 +1,526 @@
+// Package tournament runs Swiss and round-robin tournaments and single- and
+// double-elimination knockouts. Players register while a tournament is
+// open; once an operator starts it, its games are created through the game
+// manager: a round at a time, the next round being paired as soon as the
+// last game of the current one finishes, or for knockouts each match's next
+// game as soon as both its players are known. Progress is saved after every
+// change, so a restarted server picks up where it left off.
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/4-in-a-row/internal/clock"
+	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/game"
+)
+
+// Format is how a tournament pairs its rounds
+type Format string
+
+const (
+	FormatSwiss             Format = "swiss"              // players meet others on the same score
+	FormatRoundRobin        Format = "round_robin"        // every player meets every other once
+	FormatSingleElimination Format = "single_elimination" // players are out after losing a match
+	FormatDoubleElimination Format = "double_elimination" // players are out after losing two matches
+)
+
+// IsValid reports whether f is a known format
+func (f Format) IsValid() bool {
+	return f == FormatSwiss || f == FormatRoundRobin || f.IsKnockout()
+}
+
+// IsKnockout reports whether f plays a bracket of matches rather than rounds
+func (f Format) IsKnockout() bool {
+	return f == FormatSingleElimination || f == FormatDoubleElimination
+}
+
+// Tournament statuses
+const (
+	StatusRegistration = "registration"
+	StatusInProgress   = "in_progress"
+	StatusFinished     = "finished"
+)
+
+var (
+	ErrRegistrationClosed = errors.New("tournament registration is closed")
+	ErrAlreadyRegistered  = errors.New("already registered for this tournament")
+	ErrNotRegistered      = errors.New("not registered for this tournament")
+	ErrTooFewPlayers      = errors.New("tournament needs at least two players")
+	ErrNoTournamentGame   = errors.New("no tournament game in progress")
+	ErrNoBracket          = errors.New("tournament has no bracket")
+)
+
+// Tournament is a tournament with its players, in registration order until
+// it starts and by seed after, and how far it got: the pairings so far, by
+// round and board, and the standings they add up to, or for knockouts the
+// bracket
+type Tournament struct {
+	database.Tournament
+	Players      []database.TournamentPlayer  `json:"players"`
+	Pairings     []database.TournamentPairing `json:"pairings"`
+	Standings    []Standing                   `json:"standings,omitempty"`
+	Bracket      *Bracket                     `json:"bracket,omitempty"`
+	BracketGames []database.BracketGame       `json:"-"` // see Bracket.Matches
+}
+
+// Director runs tournaments. It keeps the tournaments in progress in
+// memory to route finished games back to their pairing; everything else is
+// read from the database.
+type Director struct {
+	manager *game.Manager
+	db      *database.DB
+	clock   clock.Clock
+
+	mu     sync.Mutex // serializes every change to a tournament
+	active map[string]*Tournament
+	games  map[string]string // gameID -> tournamentID, for active tournaments
+}
+
+// NewDirector creates a director that starts games through manager and
+// follows their results
+func NewDirector(manager *game.Manager, db *database.DB, clk clock.Clock) *Director {
+	d := &Director{
+		manager: manager,
+		db:      db,
+		clock:   clk,
+		active:  make(map[string]*Tournament),
+		games:   make(map[string]string),
+	}
+	manager.AddGameFinishedCallback(d.handleGameFinished)
+	return d
+}
+
+// Load resumes the tournaments that were in progress when the server last
+// stopped. Games do not survive a restart, so the unfinished games of their
+// current round are started again.
+func (d *Director) Load(ctx context.Context) error {
+	tournaments, err := d.db.ListTournaments(ctx, 0, StatusInProgress)
+	if err != nil {
+		return fmt.Errorf("failed to list tournaments: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, summary := range tournaments {
+		t, err := d.load(ctx, summary.ID)
+		if err != nil {
+			return err
+		}
+		d.active[t.ID] = t
+
+		if Format(t.Format).IsKnockout() {
+			if err := d.resumeBracketLocked(ctx, t); err != nil {
+				return fmt.Errorf("failed to save tournament %s: %w", t.ID, err)
+			}
+			log.Printf("Resumed tournament %s", t.ID)
+			continue
+		}
+
+		var restarted []database.TournamentPairing
+		for i := range t.Pairings {
+			p := &t.Pairings[i]
+			if p.Round == t.CurrentRound && p.Result == ResultPending {
+				d.startPairingLocked(t, p)
+				restarted = append(restarted, *p)
+			}
+		}
+		if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, restarted, nil); err != nil {
+			return fmt.Errorf("failed to save tournament %s: %w", t.ID, err)
+		}
+		log.Printf("Resumed tournament %s at round %d", t.ID, t.CurrentRound)
+	}
+	return nil
+}
+
+// Create opens a tournament for registration. rounds only applies to Swiss
+// tournaments, where 0 picks a number of rounds from the players when it
+// starts; the others play as many rounds as they need. bestOf, the number
+// of games a match is played over, only applies to knockouts.
+func (d *Director) Create(ctx context.Context, name string, format Format, rounds, bestOf int, rated bool) (*database.Tournament, error) {
+	if format != FormatSwiss {
+		rounds = 0
+	}
+	if !format.IsKnockout() || bestOf < 1 {
+		bestOf = 1
+	}
+	t := &database.Tournament{
+		ID:     uuid.New().String(),
+		Name:   name,
+		Format: string(format),
+		Status: StatusRegistration,
+		Rounds: rounds,
+		BestOf: bestOf,
+		Rated:  rated,
+	}
+	if err := d.db.CreateTournament(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Register signs an account up for a tournament that is still open
+func (d *Director) Register(ctx context.Context, id string, user *database.User) (*database.TournamentPlayer, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, err := d.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != StatusRegistration {
+		return nil, ErrRegistrationClosed
+	}
+	if t.player(user.Username) != nil {
+		return nil, ErrAlreadyRegistered
+	}
+
+	p := &database.TournamentPlayer{Username: user.Username, IsBot: user.IsBot, Rating: user.Rating}
+	if err := d.db.AddTournamentPlayer(ctx, id, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Withdraw takes a player off a tournament that has not started yet
+func (d *Director) Withdraw(ctx context.Context, id, username string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, err := d.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != StatusRegistration {
+		return ErrRegistrationClosed
+	}
+	if t.player(username) == nil {
+		return ErrNotRegistered
+	}
+	return d.db.RemoveTournamentPlayer(ctx, id, username)
+}
+
+// Start closes registration and starts the first round
+func (d *Director) Start(ctx context.Context, id string) (*Tournament, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, err := d.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != StatusRegistration {
+		return nil, ErrRegistrationClosed
+	}
+	if len(t.Players) < 2 {
+		return nil, ErrTooFewPlayers
+	}
+
+	switch Format(t.Format) {
+	case FormatRoundRobin:
+		t.Rounds = roundRobinRounds(len(t.Players))
+	case FormatSwiss:
+		if t.Rounds == 0 {
+			t.Rounds = swissRounds(len(t.Players))
+		}
+	}
+	now := d.clock.Now()
+	t.Status = StatusInProgress
+	t.StartedAt = &now
+
+	seed(t.Players)
+
+	d.active[t.ID] = t
+	start := d.startRoundLocked
+	if Format(t.Format).IsKnockout() {
+		start = d.advanceBracketLocked
+	}
+	if err := start(ctx, t); err != nil {
+		delete(d.active, t.ID)
+		return nil, err
+	}
+	t = t.clone()
+	t.summarize()
+	return t, nil
+}
+
+// Get returns a tournament with its standings
+func (d *Director) Get(ctx context.Context, id string) (*Tournament, error) {
+	d.mu.Lock()
+	t, err := d.load(ctx, id)
+	d.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	t.summarize()
+	return t, nil
+}
+
+// Bracket returns the bracket of a knockout that has started
+func (d *Director) Bracket(ctx context.Context, id string) (*Bracket, error) {
+	t, err := d.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Bracket == nil {
+		return nil, ErrNoBracket
+	}
+	return t.Bracket, nil
+}
+
+// List returns the most recent tournaments, only those with status unless
+// it is empty
+func (d *Director) List(ctx context.Context, limit int, status string) ([]database.Tournament, error) {
+	if status == "" {
+		return d.db.ListTournaments(ctx, limit)
+	}
+	return d.db.ListTournaments(ctx, limit, status)
+}
+
+// CurrentGame returns the game username is due to play in a tournament's
+// current round, or knockout match, and their player in it, whose session
+// token lets their client reconnect to the game
+func (d *Director) CurrentGame(id, username string) (*game.Game, *game.Player, error) {
+	d.mu.Lock()
+	var gameID string
+	if t, ok := d.active[id]; ok {
+		for _, p := range t.Pairings {
+			if p.Round == t.CurrentRound && p.Result == ResultPending &&
+				(p.Player1 == username || p.Player2 == username) {
+				gameID = p.GameID
+			}
+		}
+		for _, g := range t.BracketGames {
+			if g.Result == ResultPending && (g.Player1 == username || g.Player2 == username) {
+				gameID = g.GameID
+			}
+		}
+	}
+	d.mu.Unlock()
+	if gameID == "" {
+		return nil, nil, ErrNoTournamentGame
+	}
+
+	g, err := d.manager.GetGame(gameID)
+	if err != nil {
+		return nil, nil, ErrNoTournamentGame
+	}
+	snap := g.Snapshot()
+	for _, player := range []*game.Player{snap.Player1, snap.Player2} {
+		if player != nil && player.Username == username {
+			return g, player, nil
+		}
+	}
+	return nil, nil, ErrNoTournamentGame
+}
+
+// handleGameFinished records the result of a tournament game and, once it
+// was the last of its round, pairs the next round or ends the tournament.
+// Knockouts move on to the next game of a match or the next match instead.
+func (d *Director) handleGameFinished(snap *game.Snapshot) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id, ok := d.games[snap.ID]
+	if !ok {
+		return
+	}
+	delete(d.games, snap.ID)
+	t := d.active[id]
+	if Format(t.Format).IsKnockout() {
+		d.recordBracketGameLocked(t, snap)
+		return
+	}
+
+	var pairing *database.TournamentPairing
+	for i := range t.Pairings {
+		if t.Pairings[i].GameID == snap.ID {
+			pairing = &t.Pairings[i]
+		}
+	}
+	if pairing == nil || pairing.Result != ResultPending {
+		return
+	}
+	pairing.Result = gameResult(pairing.Player1, pairing.Player2, snap)
+	log.Printf("Tournament %s round %d board %d: %s", t.ID, pairing.Round, pairing.Board, pairing.Result)
+
+	ctx := context.Background()
+	if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, []database.TournamentPairing{*pairing}, nil); err != nil {
+		log.Printf("Failed to save tournament %s result: %v", t.ID, err)
+	}
+
+	for _, p := range t.Pairings {
+		if p.Round == t.CurrentRound && p.Result == ResultPending {
+			return
+		}
+	}
+
+	if t.CurrentRound < t.Rounds {
+		if err := d.startRoundLocked(ctx, t); err != nil {
+			log.Printf("Failed to start round %d of tournament %s: %v", t.CurrentRound, t.ID, err)
+		}
+		return
+	}
+
+	d.finishLocked(ctx, t)
+	log.Printf("Tournament %s finished after %d rounds", t.ID, t.Rounds)
+}
+
+// finishLocked ends a tournament and saves it, along with the bracket games
+// that decided it for knockouts
+func (d *Director) finishLocked(ctx context.Context, t *Tournament, games ...database.BracketGame) {
+	now := d.clock.Now()
+	t.Status = StatusFinished
+	t.FinishedAt = &now
+	delete(d.active, t.ID)
+	if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, nil, games); err != nil {
+		log.Printf("Failed to finish tournament %s: %v", t.ID, err)
+	}
+}
+
+// gameResult maps a finished game onto the pairing or bracket game of
+// player1 and player2. A game abandoned without a winner, such as one
+// neither player turned up for, is lost by both.
+func gameResult(player1, player2 string, snap *game.Snapshot) string {
+	switch {
+	case snap.Winner != nil && snap.Winner.Username == player1:
+		return ResultPlayer1Win
+	case snap.Winner != nil && snap.Winner.Username == player2:
+		return ResultPlayer2Win
+	case snap.Result == game.ResultDraw:
+		return ResultDraw
+	}
+	return ResultDoubleForfeit
+}
+
+// startRoundLocked pairs the next round, starts its games and saves it
+func (d *Director) startRoundLocked(ctx context.Context, t *Tournament) error {
+	t.CurrentRound++
+
+	var pairings []database.TournamentPairing
+	switch Format(t.Format) {
+	case FormatRoundRobin:
+		seeded := make([]string, len(t.Players))
+		for i, p := range t.Players {
+			seeded[i] = p.Username
+		}
+		pairings = roundRobinPairings(t.CurrentRound, seeded)
+	default:
+		pairings = swissPairings(t.CurrentRound, computeStandings(t.Players, t.Pairings), t.Pairings)
+	}
+
+	for i := range pairings {
+		if pairings[i].Result == ResultPending {
+			d.startPairingLocked(t, &pairings[i])
+		}
+	}
+	t.Pairings = append(t.Pairings, pairings...)
+
+	if err := d.db.SaveTournamentProgress(ctx, &t.Tournament, pairings, nil); err != nil {
+		return err
+	}
+	log.Printf("Tournament %s round %d started with %d pairings", t.ID, t.CurrentRound, len(pairings))
+	return nil
+}
+
+// startPairingLocked starts the game of a pairing. A game that cannot be
+// created is scored as lost by both players rather than holding up the
+// round.
+func (d *Director) startPairingLocked(t *Tournament, p *database.TournamentPairing) {
+	p.GameID, p.Result = d.startGameLocked(t, p.Player1, p.Player2)
+}
+
+// startGameLocked starts a tournament game and returns its ID, or if it
+// cannot be created the result it is scored as
+func (d *Director) startGameLocked(t *Tournament, player1, player2 string) (gameID, result string) {
+	g, err := d.manager.StartGame(d.newPlayer(t, player1), d.newPlayer(t, player2), game.GameOptions{Rated: t.Rated})
+	if err != nil {
+		log.Printf("Failed to start tournament %s game %s vs %s: %v", t.ID, player1, player2, err)
+		return "", ResultDoubleForfeit
+	}
+	d.games[g.ID] = t.ID
+	return g.ID, ResultPending
+}
+
+// newPlayer creates the player for a registered username. Its client takes
+// the seat by reconnecting with the player's session token.
+func (d *Director) newPlayer(t *Tournament, username string) *game.Player {
+	var player *game.Player
+	registered := t.player(username)
+	if registered != nil && registered.IsBot {
+		player = d.manager.NewBotPlayer(username)
+	} else {
+		player = d.manager.NewPlayer(username)
+	}
+	if registered != nil {
+		player.Rating = float64(registered.Rating)
+	}
+	return player
+}
+
+// load returns a tournament in progress from memory, or any other from the
+// database. d.mu must be held.
+func (d *Director) load(ctx context.Context, id string) (*Tournament, error) {
+	if t, ok := d.active[id]; ok {
+		return t.clone(), nil
+	}
+	dbT, players, pairings, err := d.db.GetTournament(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tournament{Tournament: *dbT, Players: players, Pairings: pairings}
+	if t.Status != StatusRegistration {
+		seed(t.Players)
+	}
+	if Format(t.Format).IsKnockout() {
+		if t.BracketGames, err = d.db.GetBracketGames(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// summarize fills in the standings of a round-based tournament, or the
+// bracket of a knockout that has started
+func (t *Tournament) summarize() {
+	switch {
+	case !Format(t.Format).IsKnockout():
+		t.Standings = computeStandings(t.Players, t.Pairings)
+	case t.Status != StatusRegistration:
+		t.Bracket = t.bracket()
+	}
+}
+
+// seed orders players by rating, earlier registrations first on a tie. The
+// database lists players in registration order, so a resumed tournament
+// gets the seeding it started with.
+func seed(players []database.TournamentPlayer) {
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Rating > players[j].Rating
+	})
+}
+
+func (t *Tournament) player(username string) *database.TournamentPlayer {
+	for i := range t.Players {
+		if t.Players[i].Username == username {
+			return &t.Players[i]
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) clone() *Tournament {
+	cp := *t
+	cp.Players = append([]database.TournamentPlayer(nil), t.Players...)
+	cp.Pairings = append([]database.TournamentPairing(nil), t.Pairings...)
+	cp.Standings = append([]Standing(nil), t.Standings...)
+	cp.BracketGames = append([]database.BracketGame(nil), t.BracketGames...)
+	return &cp
+}
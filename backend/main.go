@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,15 +11,48 @@ import (
 	"time"
 
 	"github.com/yourusername/4-in-a-row/internal/api"
+	"github.com/yourusername/4-in-a-row/internal/arena"
+	"github.com/yourusername/4-in-a-row/internal/auth"
+	"github.com/yourusername/4-in-a-row/internal/clock"
 	"github.com/yourusername/4-in-a-row/internal/config"
 	"github.com/yourusername/4-in-a-row/internal/database"
+	"github.com/yourusername/4-in-a-row/internal/engine"
 	"github.com/yourusername/4-in-a-row/internal/game"
 	"github.com/yourusername/4-in-a-row/internal/kafka"
+	"github.com/yourusername/4-in-a-row/internal/season"
+	"github.com/yourusername/4-in-a-row/internal/tournament"
 )
 
 func main() {
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	rules := game.Rules{
+		TurnTimeout:           cfg.TurnTimeout,
+		InactivityTimeout:     cfg.InactivityTimeout,
+		HeartbeatTimeout:      cfg.HeartbeatTimeout,
+		ReconnectWindow:       cfg.ReconnectWindow,
+		FinishedGameRetention: cfg.FinishedGameRetention,
+		MatchmakingTimeout:    cfg.MatchmakingTimeout,
+		BotMoveDelay:          cfg.BotMoveDelay,
+		MatchRatingWindow:     cfg.MatchRatingWindow,
+		MatchRatingWindowMax:  cfg.MatchRatingWindowMax,
+		MatchWindowWidenEvery: cfg.MatchWindowWidenEvery,
+		RematchCooldown:       cfg.RematchCooldown,
+		QueueStatusInterval:   cfg.QueueStatusInterval,
+	}
+	for _, q := range cfg.Queues {
+		rules.Queues = append(rules.Queues, game.QueueRules{
+			Name:               q.Name,
+			TurnTimeout:        q.TurnTimeout,
+			MatchmakingTimeout: q.MatchmakingTimeout,
+			BotFallback:        game.BotFallback(q.BotFallback),
+			Rated:              q.IsRated(),
+		})
+	}
 
 	// Initialize database
 	db, err := database.NewDB(cfg.DatabaseURL)
@@ -49,19 +83,74 @@ func main() {
 	}
 
 	// Initialize game manager
-	gameManager := game.NewManager(db, kafkaProducer)
+	gameManager := game.NewManager(db, kafkaProducer, rules, clock.Real())
+
+	// Background goroutines run until ctx is cancelled at shutdown
+	ctx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	gameManager.Start(ctx)
 
 	// Start metrics emitter (sends system metrics to Kafka every 60 seconds)
-	gameManager.StartMetricsEmitter()
+	gameManager.StartMetricsEmitter(ctx)
 
 	// Initialize matchmaking (do NOT start it yet)
-	matchmaker := game.NewMatchmaker(gameManager)
+	matchmaker := game.NewMatchmaker(gameManager, rules)
+
+	// Let an external engine take the bot's seat if one is configured
+	if cfg.EngineCommand != "" {
+		eng := engine.New(engine.Config{
+			Name:        cfg.EngineName,
+			Command:     cfg.EngineCommand,
+			Args:        cfg.EngineArgs,
+			MoveTimeout: cfg.EngineMoveTimeout,
+		}, clock.Real())
+		defer eng.Close()
+
+		gameManager.RegisterEngine(eng)
+		matchmaker.SetBotEngine(eng.Name())
+		log.Printf("External engine %s enabled: %s", eng.Name(), cfg.EngineCommand)
+	}
+
+	// Account tokens are signed with AUTH_SECRET so they survive restarts
+	secret := []byte(cfg.AuthSecret)
+	if len(secret) == 0 {
+		log.Println("Warning: AUTH_SECRET not set; login tokens will not survive a restart")
+		if secret, err = auth.RandomSecret(); err != nil {
+			log.Fatalf("Failed to generate auth secret: %v", err)
+		}
+	}
+	issuer := auth.NewIssuer(secret, cfg.AuthTokenTTL, clock.Real())
+
+	// Resume tournaments interrupted by the last shutdown
+	tournaments := tournament.NewDirector(gameManager, db, clock.Real())
+	if err := tournaments.Load(ctx); err != nil {
+		log.Printf("Warning: Failed to resume tournaments: %v", err)
+	}
+
+	// Reopen the queues of arenas that were running at the last shutdown
+	arenas := arena.NewDirector(gameManager, matchmaker, db, clock.Real())
+	if err := arenas.Load(ctx); err != nil {
+		log.Printf("Warning: Failed to resume arenas: %v", err)
+	}
+
+	// Save the configured seasons so games count towards the current one
+	seasons := make([]database.Season, len(cfg.Seasons))
+	for i, sc := range cfg.Seasons {
+		seasons[i] = database.Season{Name: sc.Name, StartsAt: sc.Starts, EndsAt: sc.Ends}
+	}
+	keeper := season.NewKeeper(db, clock.Real(), seasons)
+	if err := keeper.Load(ctx); err != nil {
+		log.Printf("Warning: Failed to load seasons: %v", err)
+	}
 
 	// Initialize API server (this registers callbacks the matchmaker relies on)
-	server := api.NewServer(cfg, gameManager, matchmaker, db)
+	server := api.NewServer(cfg, gameManager, matchmaker, db, issuer, tournaments, arenas, keeper, clock.Real())
 
-	// Now start the matchmaker loop after server (and callbacks) are ready
-	go matchmaker.Run()
+	// Now start the matchmaker, arena and season loops after server (and callbacks) are ready
+	go matchmaker.Run(ctx)
+	go arenas.Run(ctx)
+	go keeper.Run(ctx)
 
 	// Start HTTP server
 	srv := &http.Server{
@@ -80,18 +169,54 @@ func main() {
 		}
 	}()
 
-	// Graceful shutdown
+	// Start gRPC server
+	grpcServer := server.GRPCServer()
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+	go func() {
+		log.Printf("gRPC server starting on port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	// Graceful shutdown: drain on a signal or an admin request, letting
+	// active games finish before the HTTP server stops
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	select {
+	case <-quit:
+	case <-server.DrainRequested():
+	}
+
+	log.Println("Draining server...")
+	server.Drain(cfg.DrainTimeout)
 
 	log.Println("Shutting down server...")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	stopBackground()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	// Watch streams only end with their clients, so force them closed if
+	// they outlive the HTTP shutdown
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
+
 	log.Println("Server exited")
 }
@@ -1,18 +1,30 @@
 import React, { useState, useEffect, useCallback } from 'react';
 import GameBoard from './GameBoard';
 import wsService from '../services/websocket';
+import { login, register, getQueues } from '../services/api';
 import './Game.css';
 
 const Game = () => {
   const [username, setUsername] = useState('');
+  const [password, setPassword] = useState('');
+  const [queues, setQueues] = useState([]);
+  const [queue, setQueue] = useState('standard');
   const [gameState, setGameState] = useState(null);
   const [playerInfo, setPlayerInfo] = useState(null);
   const [status, setStatus] = useState('lobby'); // lobby, waiting, playing, finished
   const [error, setError] = useState('');
   const [message, setMessage] = useState('');
+  const [queueStatus, setQueueStatus] = useState(null);
   const [turnTimeLeft, setTurnTimeLeft] = useState(30);
   const [totalTimeLeft, setTotalTimeLeft] = useState(60);
 
+  // Load the matchmaking queues for the lobby's queue picker
+  useEffect(() => {
+    getQueues()
+      .then((data) => setQueues(data || []))
+      .catch(() => setQueues([]));
+  }, []);
+
   useEffect(() => {
     // Connect to WebSocket
     wsService.connect(() => {
@@ -41,6 +53,8 @@ const Game = () => {
     // Set up message handlers
     wsService.on('player_info', handlePlayerInfo);
     wsService.on('waiting', handleWaiting);
+    wsService.on('queue_status', handleQueueStatus);
+    wsService.on('search_cancelled', handleSearchCancelled);
     wsService.on('game_update', handleGameUpdate);
     wsService.on('error', handleError);
     wsService.on('reconnected', handleReconnected);
@@ -97,6 +111,20 @@ const Game = () => {
     setMessage(payload.message || 'Waiting for opponent...');
   }, []);
 
+  const handleQueueStatus = useCallback((payload) => {
+    setQueueStatus(payload);
+  }, []);
+
+  // The waiting game is gone, so drop its session and go back to the lobby
+  const handleSearchCancelled = useCallback(() => {
+    localStorage.removeItem('playerInfo');
+    localStorage.removeItem('gameSession');
+    setPlayerInfo(null);
+    setQueueStatus(null);
+    setMessage('');
+    setStatus('lobby');
+  }, []);
+
   const handleGameUpdate = useCallback((payload) => {
     setGameState(payload);
     
@@ -109,6 +137,7 @@ const Game = () => {
     if (payload.status === 'in_progress') {
       setStatus('playing');
       setMessage('');
+      setQueueStatus(null);
     } else if (payload.status === 'finished') {
       setStatus('finished');
       handleGameEnd(payload);
@@ -117,6 +146,14 @@ const Game = () => {
 
   const handleError = useCallback((payload) => {
     const errorMsg = payload.message || 'An error occurred';
+
+    // A rejected join sends the player back to the lobby
+    if (['username_taken', 'invalid_token', 'unauthorized'].includes(payload.code)) {
+      if (payload.code === 'invalid_token') {
+        localStorage.removeItem('authToken');
+      }
+      setStatus('lobby');
+    }
     
     // If reconnect fails, clear session and return to lobby
     if (errorMsg.includes('Reconnect failed') || 
@@ -186,11 +223,31 @@ const Game = () => {
   const handleJoinGame = (e) => {
     e.preventDefault();
     if (username.trim()) {
-      wsService.joinGame(username.trim());
+      wsService.joinGame(username.trim(), null, queue);
       setStatus('waiting');
     }
   };
 
+  // Log in or register, then join with the account's token
+  const handleAccount = async (action) => {
+    if (!username.trim() || !password) {
+      setError('Enter a username and password');
+      return;
+    }
+    try {
+      const data = action === 'register'
+        ? await register(username.trim(), password)
+        : await login(username.trim(), password);
+      localStorage.setItem('authToken', data.token);
+      setPassword('');
+      setError('');
+      wsService.joinGame(data.user.username, data.token, queue);
+      setStatus('waiting');
+    } catch (err) {
+      setError(err.response?.data?.error || 'Login failed');
+    }
+  };
+
   const handleManualReconnect = (e) => {
     e.preventDefault();
     const sessionToken = prompt('Enter your Session Token:');
@@ -283,7 +340,24 @@ const Game = () => {
               maxLength={20}
               required
             />
-            <button type="submit">Join Game</button>
+            <input
+              type="password"
+              placeholder="Password (for an account)"
+              value={password}
+              onChange={(e) => setPassword(e.target.value)}
+            />
+            {queues.length > 1 && (
+              <select value={queue} onChange={(e) => setQueue(e.target.value)}>
+                {queues.map((q) => (
+                  <option key={q.name} value={q.name}>
+                    {q.name} ({q.players_waiting} waiting{q.rated ? '' : ', unrated'})
+                  </option>
+                ))}
+              </select>
+            )}
+            <button type="button" onClick={() => handleAccount('login')}>Log In & Play</button>
+            <button type="button" onClick={() => handleAccount('register')}>Register</button>
+            <button type="submit">Play as Guest</button>
           </form>
           <div className="divider">OR</div>
           <button className="reconnect-btn" onClick={handleManualReconnect}>
@@ -300,7 +374,22 @@ const Game = () => {
       <div className="game-container">
         <div className="waiting">
           <h2>{message}</h2>
-          <p>A bot will join if no player is found in 10 seconds...</p>
+          {queueStatus ? (
+            <div className="queue-status">
+              <p>
+                {queueStatus.queue} queue, position {queueStatus.position} · waiting {queueStatus.elapsed_sec}s
+                {' '}· estimated {queueStatus.estimated_wait_sec}s
+              </p>
+              {queueStatus.bot_fallback_in_sec !== undefined && (
+                <p>A bot will join in {queueStatus.bot_fallback_in_sec}s if no player is found...</p>
+              )}
+            </div>
+          ) : (
+            <p>Looking for an opponent...</p>
+          )}
+          <button className="cancel-btn" onClick={() => wsService.cancelSearch()}>
+            Cancel Search
+          </button>
           
           {playerInfo && playerInfo.session_token && (
             <div className="session-info">
@@ -1,20 +1,23 @@
 import React, { useState, useEffect } from 'react';
-import { getLeaderboard } from '../services/api';
+import { getLeaderboard, getBotLeaderboard } from '../services/api';
 import './Leaderboard.css';
 
 const Leaderboard = () => {
   const [leaderboard, setLeaderboard] = useState([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState('');
+  const [showBots, setShowBots] = useState(false);
+  const [sort, setSort] = useState('rating');
 
   useEffect(() => {
     fetchLeaderboard();
-  }, []);
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [showBots, sort]);
 
   const fetchLeaderboard = async () => {
     try {
       setLoading(true);
-      const data = await getLeaderboard(10);
+      const data = showBots ? await getBotLeaderboard(10, sort) : await getLeaderboard(10, sort);
       setLeaderboard(data || []);
       setError('');
     } catch (err) {
@@ -44,16 +47,35 @@ const Leaderboard = () => {
   return (
     <div className="leaderboard-container">
       <h1>🏆 Leaderboard</h1>
+
+      <div className="leaderboard-tabs">
+        <button className={showBots ? '' : 'active'} onClick={() => setShowBots(false)}>
+          Players
+        </button>
+        <button className={showBots ? 'active' : ''} onClick={() => setShowBots(true)}>
+          Bots
+        </button>
+      </div>
+
+      <div className="leaderboard-tabs">
+        <button className={sort === 'rating' ? 'active' : ''} onClick={() => setSort('rating')}>
+          By Rating
+        </button>
+        <button className={sort === 'wins' ? 'active' : ''} onClick={() => setSort('wins')}>
+          By Wins
+        </button>
+      </div>
       
       {leaderboard.length === 0 ? (
         <div className="empty-state">
-          <p>No games played yet. Be the first!</p>
+          <p>{showBots ? 'No bot accounts have played yet.' : 'No games played yet. Be the first!'}</p>
         </div>
       ) : (
         <div className="leaderboard-table">
           <div className="table-header">
             <div className="rank">Rank</div>
-            <div className="username">Player</div>
+            <div className="username">{showBots ? 'Bot' : 'Player'}</div>
+            <div className="stats">Rating</div>
             <div className="stats">Wins</div>
             <div className="stats">Losses</div>
             <div className="stats">Draws</div>
@@ -73,6 +95,9 @@ const Leaderboard = () => {
                   {index > 2 && `#${index + 1}`}
                 </div>
                 <div className="username">{player.username}</div>
+                <div className="stats" title={player.provisional ? 'Provisional rating' : `±${player.rating_deviation * 2}`}>
+                  {player.rating}{player.provisional && '?'}
+                </div>
                 <div className="stats">{player.games_won}</div>
                 <div className="stats">{player.games_lost}</div>
                 <div className="stats">{player.games_drawn}</div>
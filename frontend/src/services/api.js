@@ -10,8 +10,28 @@ const api = axios.create({
   timeout: 10000,
 });
 
-export const getLeaderboard = async (limit = 10) => {
-  const response = await api.get(`/leaderboard?limit=${limit}`);
+export const register = async (username, password) => {
+  const response = await api.post('/auth/register', { username, password });
+  return response.data;
+};
+
+export const login = async (username, password) => {
+  const response = await api.post('/auth/login', { username, password });
+  return response.data;
+};
+
+export const getLeaderboard = async (limit = 10, sort = 'wins') => {
+  const response = await api.get(`/leaderboard?limit=${limit}&sort=${sort}`);
+  return response.data;
+};
+
+export const getBotLeaderboard = async (limit = 10, sort = 'wins') => {
+  const response = await api.get(`/leaderboard/bots?limit=${limit}&sort=${sort}`);
+  return response.data;
+};
+
+export const getQueues = async () => {
+  const response = await api.get('/queues');
   return response.data;
 };
 
@@ -20,6 +40,11 @@ export const getUserStats = async (username) => {
   return response.data;
 };
 
+export const getRatingHistory = async (username, limit = 20) => {
+  const response = await api.get(`/user/${username}/ratings?limit=${limit}`);
+  return response.data;
+};
+
 export const getRecentGames = async (limit = 20) => {
   const response = await api.get(`/games/recent?limit=${limit}`);
   return response.data;
@@ -113,16 +113,25 @@ class WebSocketService {
     }
   }
 
-  joinGame(username) {
-    this.send('join', { username });
+  // Join with an account token, or as a guest under username
+  joinGame(username, token, queue) {
+    if (token) {
+      this.send('join', { token, queue });
+    } else {
+      this.send('join', { username, guest: true, queue });
+    }
   }
 
   makeMove(column) {
     this.send('move', { column });
   }
 
-  reconnect(playerId, gameId) {
-    this.send('reconnect', { player_id: playerId, game_id: gameId });
+  cancelSearch() {
+    this.send('cancel_search', {});
+  }
+
+  reconnect(sessionToken) {
+    this.send('reconnect', { session_token: sessionToken });
   }
 }
 